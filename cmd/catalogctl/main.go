@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	defaultURL     = "http://localhost:8080"
+	importPath     = "/api/v1/catalog/products:import"
+	requestTimeout = 10 * time.Minute
+)
+
+type errorResponse struct {
+	Code    uint32 `json:"code"`
+	Message string `json:"message"`
+}
+
+type importRowResult struct {
+	Row    int            `json:"row"`
+	SKU    string         `json:"sku"`
+	ID     string         `json:"id"`
+	Status string         `json:"status"`
+	Error  *errorResponse `json:"error"`
+}
+
+type importProductsResponse struct {
+	Created int                `json:"created"`
+	Updated int                `json:"updated"`
+	Failed  int                `json:"failed"`
+	Rows    []*importRowResult `json:"rows"`
+}
+
+var contentTypes = map[string]string{
+	"csv":    "text/csv",
+	"ndjson": "application/x-ndjson",
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "import":
+		os.Exit(runImport(os.Args[2:]))
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: catalogctl import [flags] FILE")
+	os.Exit(2)
+}
+
+// runImport uploads a CSV or NDJSON file to the catalog service and prints the failed rows of the report.
+// The exit code is 1 when any row has failed.
+func runImport(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	serviceURL := flags.String("url", envString("CATALOG_URL", defaultURL), "base URL of the catalog service")
+	format := flags.String("format", "", "format of the file, csv or ndjson; detected by the file extension when omitted")
+	actor := flags.String("actor", os.Getenv("USER"), "user on whose behalf products are changed")
+	reportPath := flags.String("report", "", "write the full JSON report to the file")
+	_ = flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+	}
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = formatByExtension(path)
+	}
+	contentType, ok := contentTypes[*format]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown import format '%s', use -format csv or -format ndjson\n", *format)
+		return 2
+	}
+
+	var file io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		file = f
+	}
+	report, err := importProducts(*serviceURL, contentType, *actor, file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *reportPath != "" {
+		if err = writeReport(*reportPath, report); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	for _, row := range report.Rows {
+		if row.Status == "failed" && row.Error != nil {
+			fmt.Fprintf(os.Stderr, "row %d (%s): %s\n", row.Row, row.SKU, row.Error.Message)
+		}
+	}
+	fmt.Printf("created: %d, updated: %d, failed: %d\n", report.Created, report.Updated, report.Failed)
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func importProducts(serviceURL, contentType, actor string, body io.Reader) (*importProductsResponse, error) {
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(serviceURL, "/")+importPath, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if actor != "" {
+		req.Header.Set("X-Actor", actor)
+	}
+	client := &http.Client{Timeout: requestTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		if err = json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Message == "" {
+			return nil, fmt.Errorf("import failed: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("import failed: %s (code %d)", errResp.Message, errResp.Code)
+	}
+	var report importProductsResponse
+	if err = json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, fmt.Errorf("can't decode import report: %v", err)
+	}
+	return &report, nil
+}
+
+func writeReport(path string, report *importProductsResponse) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(report); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func formatByExtension(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return "csv"
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	default:
+		return ""
+	}
+}
+
+func envString(env, fallback string) string {
+	e := os.Getenv(env)
+	if e == "" {
+		return fallback
+	}
+	return e
+}
@@ -2,9 +2,14 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
@@ -14,12 +19,17 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 
 	"github.com/jnikolaeva/eshop-common/httpkit"
 	postgresadapter "github.com/jnikolaeva/eshop-common/postgres"
 
 	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+	"github.com/jnikolaeva/catalogservice/internal/catalog/infrastructure/events"
+	grpctransport "github.com/jnikolaeva/catalogservice/internal/catalog/infrastructure/grpc"
+	"github.com/jnikolaeva/catalogservice/internal/catalog/infrastructure/grpc/pb"
 	httptransport "github.com/jnikolaeva/catalogservice/internal/catalog/infrastructure/http"
+	"github.com/jnikolaeva/catalogservice/internal/catalog/infrastructure/webhook"
 
 	"github.com/jnikolaeva/catalogservice/internal/probes"
 
@@ -27,12 +37,29 @@ import (
 )
 
 const (
-	appName     = "catalogservice"
-	defaultPort = "8080"
+	appName               = "catalogservice"
+	defaultPort           = "8080"
+	defaultGRPCPort       = "9090"
+	defaultReservationTTL = 15 * time.Minute
+
+	defaultReservationExpiryInterval = 30 * time.Second
+	defaultOutboxInterval            = time.Second
+	defaultEventsExchange            = "catalog.events"
+	outboxBatchSize                  = 100
+	defaultWebhookInterval           = 5 * time.Second
+	defaultWebhookTimeout            = 10 * time.Second
+	defaultWebhookMaxAttempts        = 8
+	webhookInitialBackoff            = 30 * time.Second
+	webhookMaxBackoff                = time.Hour
+	defaultImportWorkers             = 2
+	defaultImportInterval            = 2 * time.Second
+	defaultImportLease               = time.Minute
+	defaultFeedTitle                 = "Product catalog"
 )
 
 func main() {
 	serverAddr := ":" + envString("APP_PORT", defaultPort)
+	grpcServerAddr := ":" + envString("APP_GRPC_PORT", defaultGRPCPort)
 
 	logger := logrus.New()
 	logger.SetOutput(os.Stdout)
@@ -62,8 +89,20 @@ func main() {
 	defer connectionPool.Close()
 
 	repository := postgres.New(connectionPool)
-	service := application.NewService(repository)
-	endpoints := httptransport.MakeEndpoints(service)
+	normalization := skuNormalization(logger)
+	priceListRepository := postgres.NewPriceListRepository(connectionPool)
+	service := application.NewService(repository, priceListRepository, normalization)
+	categoryService := application.NewCategoryService(postgres.NewCategoryRepository(connectionPool))
+	reservationRepository := postgres.NewReservationRepository(connectionPool)
+	reservationService := application.NewReservationService(reservationRepository, envDuration(logger, "APP_RESERVATION_TTL", defaultReservationTTL))
+	stockService := application.NewStockService(postgres.NewStockRepository(connectionPool))
+	warehouseService := application.NewWarehouseService(postgres.NewWarehouseRepository(connectionPool))
+	webhookRepository := postgres.NewWebhookRepository(connectionPool)
+	webhookService := application.NewWebhookService(webhookRepository)
+	importJobRepository := postgres.NewImportJobRepository(connectionPool)
+	importJobService := application.NewImportJobService(importJobRepository)
+	priceListService := application.NewPriceListService(priceListRepository)
+	endpoints := httptransport.MakeEndpoints(service, categoryService, reservationService, stockService, warehouseService, webhookService, importJobService, priceListService, merchantFeed(), cursorSecret(logger))
 
 	metrics := httpkit.NewMetricsHolder(gokitprometheus.NewCounterFrom(prometheus.CounterOpts{
 		Namespace: "catalog",
@@ -84,10 +123,106 @@ func main() {
 	mux.Handle("/live", probes.MakeLiveHandler())
 	mux.Handle("/metrics", promhttp.Handler())
 
+	expiryWorker := application.NewReservationExpiryWorker(
+		reservationRepository,
+		envDuration(logger, "APP_RESERVATION_EXPIRY_INTERVAL", defaultReservationExpiryInterval),
+		errorLogger,
+		gokitprometheus.NewCounterFrom(prometheus.CounterOpts{
+			Namespace: "catalog",
+			Name:      "reservations_expired_total",
+			Help:      "Number of stock reservations released after their TTL.",
+		}, nil),
+		gokitprometheus.NewCounterFrom(prometheus.CounterOpts{
+			Namespace: "catalog",
+			Name:      "reservations_expired_quantity_total",
+			Help:      "Units of stock returned to products by expired reservations.",
+		}, nil))
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		expiryWorker.Run(workerCtx)
+	}()
+
+	if publisher, closer := eventPublisher(logger); publisher != nil {
+		defer closer.Close()
+		relay := application.NewOutboxRelay(
+			postgres.NewOutboxRepository(connectionPool),
+			publisher,
+			envDuration(logger, "APP_OUTBOX_INTERVAL", defaultOutboxInterval),
+			outboxBatchSize,
+			errorLogger,
+			gokitprometheus.NewCounterFrom(prometheus.CounterOpts{
+				Namespace: "catalog",
+				Name:      "outbox_published_total",
+				Help:      "Number of domain events published from the outbox.",
+			}, nil))
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			relay.Run(workerCtx)
+		}()
+	}
+
+	webhookDispatcher := application.NewWebhookDispatcher(
+		webhookRepository,
+		webhook.NewHTTPSender(envDuration(logger, "APP_WEBHOOK_TIMEOUT", defaultWebhookTimeout)),
+		application.WebhookRetryPolicy{
+			MaxAttempts:    envInt(logger, "APP_WEBHOOK_MAX_ATTEMPTS", defaultWebhookMaxAttempts),
+			InitialBackoff: webhookInitialBackoff,
+			MaxBackoff:     webhookMaxBackoff,
+		},
+		envDuration(logger, "APP_WEBHOOK_INTERVAL", defaultWebhookInterval),
+		errorLogger,
+		gokitprometheus.NewCounterFrom(prometheus.CounterOpts{
+			Namespace: "catalog",
+			Name:      "webhook_deliveries_total",
+			Help:      "Number of webhook delivery attempts by result.",
+		}, []string{"result"}),
+		gokitprometheus.NewHistogramFrom(prometheus.HistogramOpts{
+			Namespace: "catalog",
+			Name:      "webhook_delivery_duration_seconds",
+			Help:      "Duration of webhook delivery attempts in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		}, nil))
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		webhookDispatcher.Run(workerCtx)
+	}()
+
+	importWorker := application.NewImportWorker(
+		importJobRepository,
+		repository,
+		normalization,
+		httptransport.DecodeImportRows,
+		envInt(logger, "APP_IMPORT_WORKERS", defaultImportWorkers),
+		envDuration(logger, "APP_IMPORT_INTERVAL", defaultImportInterval),
+		envDuration(logger, "APP_IMPORT_LEASE", defaultImportLease),
+		errorLogger,
+		gokitprometheus.NewCounterFrom(prometheus.CounterOpts{
+			Namespace: "catalog",
+			Name:      "import_rows_total",
+			Help:      "Number of rows of import jobs committed by status.",
+		}, []string{"status"}))
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		importWorker.Run(workerCtx)
+	}()
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterCatalogServer(grpcServer, grpctransport.NewServer(endpoints, errorLogger))
+
 	srv := startServer(serverAddr, mux, logger)
+	startGRPCServer(grpcServerAddr, grpcServer, logger)
 
 	waitForShutdown(srv)
+	grpcServer.GracefulStop()
 	logger.Info("shutting down")
+	stopWorkers()
+	workers.Wait()
 }
 
 func startServer(serverAddr string, handler http.Handler, logger *logrus.Logger) *http.Server {
@@ -101,6 +236,18 @@ func startServer(serverAddr string, handler http.Handler, logger *logrus.Logger)
 	return srv
 }
 
+func startGRPCServer(serverAddr string, srv *grpc.Server, logger *logrus.Logger) {
+	listener, err := net.Listen("tcp", serverAddr)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	go func() {
+		logger.WithFields(logrus.Fields{"url": serverAddr}).Info("starting the gRPC server")
+		logger.Fatal(srv.Serve(listener))
+	}()
+}
+
 func waitForShutdown(srv *http.Server) {
 	killSignalChan := make(chan os.Signal, 1)
 	signal.Notify(killSignalChan, os.Kill, os.Interrupt, syscall.SIGTERM)
@@ -109,6 +256,93 @@ func waitForShutdown(srv *http.Server) {
 	_ = srv.Shutdown(context.Background())
 }
 
+// cursorSecret falls back to a random key, so cursors issued by one instance are rejected by the others
+func cursorSecret(logger *logrus.Logger) []byte {
+	if secret := os.Getenv("APP_CURSOR_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	logger.Warn("APP_CURSOR_SECRET is not set, pagination cursors are signed with a random key")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		logger.Fatal(err.Error())
+	}
+	return secret
+}
+
+// eventPublisher returns nil when no publisher is configured, the events are kept in the outbox until one is
+func eventPublisher(logger *logrus.Logger) (application.EventPublisher, io.Closer) {
+	if url := os.Getenv("APP_EVENTS_AMQP_URL"); url != "" {
+		publisher, err := events.NewAMQPPublisher(url, envString("APP_EVENTS_EXCHANGE", defaultEventsExchange))
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		return publisher, publisher
+	}
+	if path := os.Getenv("APP_EVENTS_FILE"); path != "" {
+		publisher, closer, err := events.NewFilePublisher(path)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		return publisher, closer
+	}
+	logger.Warn("no event publisher is configured, domain events are kept in the outbox")
+	return nil, nil
+}
+
+// merchantFeed describes the shop in the Google Merchant Center feed export
+func merchantFeed() httptransport.MerchantFeed {
+	return httptransport.MerchantFeed{
+		Title:       envString("APP_FEED_TITLE", defaultFeedTitle),
+		Link:        os.Getenv("APP_FEED_LINK"),
+		Description: envString("APP_FEED_DESCRIPTION", defaultFeedTitle),
+		ProductURL:  os.Getenv("APP_FEED_PRODUCT_URL"),
+	}
+}
+
+// skuNormalization trims SKUs and keeps their case by default
+func skuNormalization(logger *logrus.Logger) application.SKUNormalization {
+	normalization := application.SKUNormalization{TrimSpace: true, Case: application.SKUCasePreserve}
+	if value := os.Getenv("APP_SKU_TRIM"); value != "" {
+		trim, err := strconv.ParseBool(value)
+		if err != nil {
+			logger.Fatalf("invalid APP_SKU_TRIM value '%s'", value)
+		}
+		normalization.TrimSpace = trim
+	}
+	if value := os.Getenv("APP_SKU_CASE"); value != "" {
+		c, err := application.ParseSKUCase(value)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		normalization.Case = c
+	}
+	return normalization
+}
+
+func envDuration(logger *logrus.Logger, env string, fallback time.Duration) time.Duration {
+	value := os.Getenv(env)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		logger.Fatalf("invalid %s value '%s'", env, value)
+	}
+	return d
+}
+
+func envInt(logger *logrus.Logger, env string, fallback int) int {
+	value := os.Getenv(env)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		logger.Fatalf("invalid %s value '%s'", env, value)
+	}
+	return n
+}
+
 func envString(env, fallback string) string {
 	e := os.Getenv(env)
 	if e == "" {
@@ -0,0 +1 @@
+ALTER TABLE products ADD version INTEGER NOT NULL DEFAULT 1;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID NOT NULL PRIMARY KEY,
+    parent_id UUID REFERENCES categories (id),
+    slug VARCHAR(256) NOT NULL UNIQUE,
+    name VARCHAR(256) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx ON categories (parent_id);
+
+CREATE TABLE IF NOT EXISTS product_categories (
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (product_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS product_categories_category_id_idx ON product_categories (category_id);
\ No newline at end of file
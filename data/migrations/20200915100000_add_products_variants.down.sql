@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS products_parent_id_idx;
+ALTER TABLE products DROP COLUMN options;
+ALTER TABLE products DROP COLUMN variant_axes;
+ALTER TABLE products DROP COLUMN parent_id;
\ No newline at end of file
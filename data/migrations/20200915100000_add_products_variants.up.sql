@@ -0,0 +1,5 @@
+ALTER TABLE products ADD parent_id UUID REFERENCES products (id) ON DELETE CASCADE;
+ALTER TABLE products ADD variant_axes JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE products ADD options JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS products_parent_id_idx ON products (parent_id);
\ No newline at end of file
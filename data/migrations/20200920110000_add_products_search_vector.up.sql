@@ -0,0 +1,8 @@
+ALTER TABLE products ADD search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', title), 'A') ||
+    setweight(to_tsvector('simple', sku), 'A') ||
+    setweight(to_tsvector('simple', color), 'B') ||
+    setweight(to_tsvector('simple', material), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS products_search_vector_idx ON products USING GIN (search_vector);
\ No newline at end of file
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS reservations (
+    id UUID NOT NULL PRIMARY KEY,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS reservations_pending_expires_at_idx ON reservations (expires_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS reservation_items (
+    reservation_id UUID NOT NULL REFERENCES reservations (id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (reservation_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS reservation_items_product_id_idx ON reservation_items (product_id);
\ No newline at end of file
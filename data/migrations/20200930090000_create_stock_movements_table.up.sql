@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id BIGSERIAL PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    delta INTEGER NOT NULL,
+    balance INTEGER NOT NULL,
+    reason VARCHAR(32) NOT NULL,
+    actor VARCHAR(256),
+    reference VARCHAR(256),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS stock_movements_product_id_idx ON stock_movements (product_id, id);
+
+INSERT INTO stock_movements (product_id, delta, balance, reason)
+SELECT id, available_qty, available_qty, 'initial' FROM products;
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE reservation_items DROP CONSTRAINT reservation_items_pkey;
+DELETE FROM reservation_items a USING reservation_items b
+    WHERE a.reservation_id = b.reservation_id AND a.product_id = b.product_id AND a.warehouse_id > b.warehouse_id;
+ALTER TABLE reservation_items ADD PRIMARY KEY (reservation_id, product_id);
+ALTER TABLE reservation_items DROP COLUMN IF EXISTS warehouse_id;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS warehouse_id;
+DROP TABLE IF EXISTS warehouse_stock;
+DROP TABLE IF EXISTS warehouses;
\ No newline at end of file
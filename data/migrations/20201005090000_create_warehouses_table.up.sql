@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS warehouses (
+    id UUID NOT NULL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(256) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS warehouses_default_idx ON warehouses (is_default) WHERE is_default;
+
+INSERT INTO warehouses (id, code, name, is_default)
+VALUES ('00000000-0000-0000-0000-000000000001', 'default', 'Default warehouse', TRUE);
+
+CREATE TABLE IF NOT EXISTS warehouse_stock (
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    warehouse_id UUID NOT NULL REFERENCES warehouses (id),
+    available_qty INTEGER NOT NULL CHECK (available_qty >= 0),
+    PRIMARY KEY (product_id, warehouse_id)
+);
+
+CREATE INDEX IF NOT EXISTS warehouse_stock_warehouse_id_idx ON warehouse_stock (warehouse_id, available_qty);
+
+INSERT INTO warehouse_stock (product_id, warehouse_id, available_qty)
+SELECT id, '00000000-0000-0000-0000-000000000001', GREATEST(available_qty, 0) FROM products;
+
+ALTER TABLE stock_movements ADD warehouse_id UUID REFERENCES warehouses (id);
+UPDATE stock_movements SET warehouse_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE stock_movements ALTER warehouse_id SET NOT NULL;
+
+ALTER TABLE reservation_items ADD warehouse_id UUID REFERENCES warehouses (id);
+UPDATE reservation_items SET warehouse_id = '00000000-0000-0000-0000-000000000001';
+ALTER TABLE reservation_items ALTER warehouse_id SET NOT NULL;
+ALTER TABLE reservation_items DROP CONSTRAINT reservation_items_pkey;
+ALTER TABLE reservation_items ADD PRIMARY KEY (reservation_id, product_id, warehouse_id);
\ No newline at end of file
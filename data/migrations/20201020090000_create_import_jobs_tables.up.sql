@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id UUID NOT NULL PRIMARY KEY,
+    format VARCHAR(16) NOT NULL,
+    actor VARCHAR(256) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    content BYTEA NOT NULL,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    created_count INTEGER NOT NULL DEFAULT 0,
+    updated_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    locked_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS import_jobs_unfinished_idx ON import_jobs (created_at) WHERE status IN ('pending', 'running');
+
+CREATE TABLE IF NOT EXISTS import_job_errors (
+    job_id UUID NOT NULL REFERENCES import_jobs (id) ON DELETE CASCADE,
+    row_number INTEGER NOT NULL,
+    sku TEXT NOT NULL DEFAULT '',
+    message TEXT NOT NULL,
+    PRIMARY KEY (job_id, row_number)
+);
\ No newline at end of file
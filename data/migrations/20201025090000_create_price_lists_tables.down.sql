@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS price_list_entries;
+DROP TABLE IF EXISTS price_lists;
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
\ No newline at end of file
@@ -0,0 +1,19 @@
+ALTER TABLE products ADD currency VARCHAR(3) NOT NULL DEFAULT 'USD';
+
+CREATE TABLE IF NOT EXISTS price_lists (
+    id UUID NOT NULL PRIMARY KEY,
+    name VARCHAR(256) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    market VARCHAR(64) NOT NULL DEFAULT '',
+    customer_group VARCHAR(64) NOT NULL DEFAULT '',
+    UNIQUE (currency, market, customer_group)
+);
+
+CREATE TABLE IF NOT EXISTS price_list_entries (
+    price_list_id UUID NOT NULL REFERENCES price_lists (id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    price DECIMAL NOT NULL,
+    PRIMARY KEY (price_list_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS price_list_entries_product_id_idx ON price_list_entries (product_id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS product_prices;
\ No newline at end of file
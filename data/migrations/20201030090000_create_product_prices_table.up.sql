@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS product_prices (
+    id UUID NOT NULL PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    price_list_id UUID REFERENCES price_lists (id) ON DELETE CASCADE,
+    price DECIMAL NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS product_prices_product_id_starts_at_idx ON product_prices (product_id, starts_at);
\ No newline at end of file
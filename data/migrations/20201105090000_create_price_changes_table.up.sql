@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS price_changes (
+    id BIGSERIAL PRIMARY KEY,
+    product_id UUID NOT NULL,
+    sku VARCHAR(256) NOT NULL,
+    price_list_id UUID,
+    scheduled_price_id UUID,
+    reason VARCHAR(32) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    old_price DECIMAL,
+    new_price DECIMAL,
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    actor VARCHAR(256),
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS price_changes_product_id_changed_at_idx ON price_changes (product_id, changed_at);
+CREATE INDEX IF NOT EXISTS price_changes_sku_changed_at_idx ON price_changes (sku, changed_at);
+
+INSERT INTO price_changes (product_id, sku, reason, currency, new_price)
+SELECT id, sku, 'initial', currency, price FROM products;
+
+INSERT INTO price_changes (product_id, sku, price_list_id, reason, currency, new_price)
+SELECT e.product_id, p.sku, e.price_list_id, 'update', l.currency, e.price FROM price_list_entries e
+JOIN price_lists l ON l.id = e.price_list_id
+JOIN products p ON p.id = e.product_id;
+
+INSERT INTO price_changes (product_id, sku, price_list_id, scheduled_price_id, reason, currency, new_price,
+    starts_at, ends_at, changed_at)
+SELECT s.product_id, p.sku, s.price_list_id, s.id, 'sale_scheduled', COALESCE(l.currency, p.currency), s.price,
+    s.starts_at, s.ends_at, s.created_at FROM product_prices s
+LEFT JOIN price_lists l ON l.id = s.price_list_id
+JOIN products p ON p.id = s.product_id;
\ No newline at end of file
@@ -4,9 +4,14 @@ go 1.14
 
 require (
 	github.com/go-kit/kit v0.10.0
+	github.com/golang/protobuf v1.3.2
 	github.com/gorilla/mux v1.7.3
 	github.com/jackc/pgx v3.6.2+incompatible
 	github.com/jnikolaeva/eshop-common v0.0.0-20200820085559-b4f837ad4596
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.3.0
 	github.com/shopspring/decimal v1.2.0
+	github.com/sirupsen/logrus v1.4.2
+	github.com/streadway/amqp v0.0.0-20190827072141-edfb9018d271
+	google.golang.org/grpc v1.26.0
 )
@@ -0,0 +1,40 @@
+package application
+
+import (
+	"github.com/jnikolaeva/eshop-common/uuid"
+)
+
+type CategoryID uuid.UUID
+
+func (u CategoryID) String() string {
+	return uuid.UUID(u).String()
+}
+
+type Category struct {
+	ID       CategoryID
+	ParentID *CategoryID
+	Slug     string
+	Name     string
+	Position int
+}
+
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode
+}
+
+type CategoryFilter struct {
+	ID                 CategoryID
+	IncludeDescendants bool
+}
+
+type CategoryRepository interface {
+	NextID() CategoryID
+	FindByID(id CategoryID) (*Category, error)
+	FindAll() ([]*Category, error)
+	FindByProduct(productID ProductID) ([]*Category, error)
+	Add(item Category) error
+	Update(item Category) error
+	Delete(id CategoryID) error
+	SetProductCategories(productID ProductID, ids []CategoryID) error
+}
@@ -0,0 +1,154 @@
+package application
+
+import (
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrDuplicateCategory   = errors.New("category with such slug already exists")
+	ErrInvalidParent       = errors.New("parent category does not exist or creates a cycle")
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+)
+
+type CategoryParams interface {
+	GetParentID() *uuid.UUID
+	GetSlug() string
+	GetName() string
+	GetPosition() int
+}
+
+type CategoryService interface {
+	FindByID(id uuid.UUID) (*Category, error)
+	Tree() ([]*CategoryNode, error)
+	Create(params CategoryParams) (CategoryID, error)
+	Update(id uuid.UUID, params CategoryParams) (*Category, error)
+	Delete(id uuid.UUID) error
+	FindByProduct(productID uuid.UUID) ([]*Category, error)
+	SetProductCategories(productID uuid.UUID, ids []uuid.UUID) error
+}
+
+type categoryService struct {
+	repo CategoryRepository
+}
+
+func NewCategoryService(repository CategoryRepository) CategoryService {
+	return &categoryService{repo: repository}
+}
+
+func (s *categoryService) FindByID(id uuid.UUID) (*Category, error) {
+	return s.repo.FindByID(CategoryID(id))
+}
+
+func (s *categoryService) Tree() ([]*CategoryNode, error) {
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return buildTree(items), nil
+}
+
+func (s *categoryService) Create(params CategoryParams) (CategoryID, error) {
+	item := newCategory(s.repo.NextID(), params)
+	if err := s.checkParent(item); err != nil {
+		return CategoryID{}, err
+	}
+	if err := s.repo.Add(*item); err != nil {
+		return CategoryID{}, errors.WithStack(err)
+	}
+	return item.ID, nil
+}
+
+func (s *categoryService) Update(id uuid.UUID, params CategoryParams) (*Category, error) {
+	if _, err := s.repo.FindByID(CategoryID(id)); err != nil {
+		return nil, err
+	}
+	item := newCategory(CategoryID(id), params)
+	if err := s.checkParent(item); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(*item); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return item, nil
+}
+
+func (s *categoryService) Delete(id uuid.UUID) error {
+	return s.repo.Delete(CategoryID(id))
+}
+
+func (s *categoryService) FindByProduct(productID uuid.UUID) ([]*Category, error) {
+	return s.repo.FindByProduct(ProductID(productID))
+}
+
+func (s *categoryService) SetProductCategories(productID uuid.UUID, ids []uuid.UUID) error {
+	categoryIDs := make([]CategoryID, len(ids))
+	for i, id := range ids {
+		categoryIDs[i] = CategoryID(id)
+	}
+	return s.repo.SetProductCategories(ProductID(productID), categoryIDs)
+}
+
+// checkParent makes sure the parent exists and is not the category itself or one of its descendants.
+func (s *categoryService) checkParent(item *Category) error {
+	if item.ParentID == nil {
+		return nil
+	}
+	items, err := s.repo.FindAll()
+	if err != nil {
+		return err
+	}
+	parents := make(map[CategoryID]*CategoryID, len(items))
+	for _, c := range items {
+		parents[c.ID] = c.ParentID
+	}
+	steps := 0
+	for id := item.ParentID; id != nil; id = parents[*id] {
+		steps++
+		if *id == item.ID || steps > len(items) {
+			return ErrInvalidParent
+		}
+		if _, ok := parents[*id]; !ok {
+			return ErrInvalidParent
+		}
+	}
+	return nil
+}
+
+func newCategory(id CategoryID, params CategoryParams) *Category {
+	item := &Category{
+		ID:       id,
+		Slug:     params.GetSlug(),
+		Name:     params.GetName(),
+		Position: params.GetPosition(),
+	}
+	if parentID := params.GetParentID(); parentID != nil {
+		categoryID := CategoryID(*parentID)
+		item.ParentID = &categoryID
+	}
+	return item
+}
+
+// buildTree expects items ordered by position and keeps that order among siblings.
+func buildTree(items []*Category) []*CategoryNode {
+	nodes := make(map[CategoryID]*CategoryNode, len(items))
+	for _, item := range items {
+		nodes[item.ID] = &CategoryNode{Category: *item}
+	}
+	var roots []*CategoryNode
+	for _, item := range items {
+		node := nodes[item.ID]
+		if item.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		parent, ok := nodes[*item.ParentID]
+		if !ok {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	return roots
+}
@@ -0,0 +1,40 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+)
+
+type EventType string
+
+const (
+	EventProductCreated EventType = "ProductCreated"
+	EventProductUpdated EventType = "ProductUpdated"
+	EventProductDeleted EventType = "ProductDeleted"
+	EventStockChanged   EventType = "StockChanged"
+)
+
+// Event is a domain event stored in the outbox in the same transaction as the change it describes.
+// Payload is the JSON encoded state of the aggregate after the change.
+type Event struct {
+	ID          uuid.UUID
+	Type        EventType
+	AggregateID ProductID
+	Payload     []byte
+	OccurredAt  time.Time
+}
+
+// EventPublisher delivers events to other services. Delivery is at least once, consumers are expected
+// to deduplicate events by ID.
+type EventPublisher interface {
+	Publish(ctx context.Context, events []Event) error
+}
+
+type OutboxRepository interface {
+	// PublishPending locks up to limit unpublished events in the order they were stored, skipping events
+	// locked by other relays, passes them to publish and marks them published if it succeeds.
+	// It returns the number of published events.
+	PublishPending(limit int, publish func(events []Event) error) (int, error)
+}
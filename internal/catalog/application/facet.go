@@ -0,0 +1,58 @@
+package application
+
+import (
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+type FacetField string
+
+const (
+	FacetColor    FacetField = "color"
+	FacetMaterial FacetField = "material"
+	FacetPrice    FacetField = "price"
+)
+
+var ErrUnsupportedFacet = errors.New("unsupported facet")
+
+type FacetSpec struct {
+	Fields []FacetField
+	// PriceBuckets are ascending bucket boundaries, values below the first one and above the last one
+	// fall into open-ended buckets
+	PriceBuckets []decimal.Decimal
+}
+
+func NewFacetField(field string) (FacetField, error) {
+	f := FacetField(field)
+	switch f {
+	case FacetColor, FacetMaterial, FacetPrice:
+		return f, nil
+	}
+	return "", errors.Wrapf(ErrUnsupportedFacet, "'%s'", field)
+}
+
+func (s FacetSpec) Contains(field FacetField) bool {
+	for _, f := range s.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+type FacetValue struct {
+	Value string
+	Count int
+}
+
+type PriceBucket struct {
+	From  *decimal.Decimal
+	To    *decimal.Decimal
+	Count int
+}
+
+type Facets struct {
+	Color    []FacetValue
+	Material []FacetValue
+	Price    []PriceBucket
+}
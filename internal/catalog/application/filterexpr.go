@@ -0,0 +1,399 @@
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+var ErrInvalidFilterExpr = errors.New("invalid filter expression")
+
+type FilterField string
+
+const (
+	FilterFieldTitle        FilterField = "title"
+	FilterFieldSKU          FilterField = "sku"
+	FilterFieldPrice        FilterField = "price"
+	FilterFieldAvailableQty FilterField = "available_qty"
+	FilterFieldColor        FilterField = "color"
+	FilterFieldMaterial     FilterField = "material"
+	FilterFieldParentID     FilterField = "parent_id"
+)
+
+type FilterFieldType int
+
+const (
+	FilterTypeString FilterFieldType = iota
+	FilterTypeDecimal
+	FilterTypeInteger
+	FilterTypeUUID
+)
+
+var filterFieldTypes = map[FilterField]FilterFieldType{
+	FilterFieldTitle:        FilterTypeString,
+	FilterFieldSKU:          FilterTypeString,
+	FilterFieldPrice:        FilterTypeDecimal,
+	FilterFieldAvailableQty: FilterTypeInteger,
+	FilterFieldColor:        FilterTypeString,
+	FilterFieldMaterial:     FilterTypeString,
+	FilterFieldParentID:     FilterTypeUUID,
+}
+
+func (f FilterField) Type() FilterFieldType {
+	return filterFieldTypes[f]
+}
+
+type CompareOp string
+
+const (
+	OpEq     CompareOp = "eq"
+	OpNe     CompareOp = "ne"
+	OpGt     CompareOp = "gt"
+	OpGte    CompareOp = "gte"
+	OpLt     CompareOp = "lt"
+	OpLte    CompareOp = "lte"
+	OpIn     CompareOp = "in"
+	OpNin    CompareOp = "nin"
+	OpLike   CompareOp = "like"
+	OpExists CompareOp = "exists"
+)
+
+type LogicalOp string
+
+const (
+	OpAnd LogicalOp = "and"
+	OpOr  LogicalOp = "or"
+)
+
+// FilterExpr is a node of a parsed filter expression: *LogicalExpr, *NotExpr or *Comparison.
+type FilterExpr interface {
+	filterExpr()
+}
+
+type LogicalExpr struct {
+	Op       LogicalOp
+	Operands []FilterExpr
+}
+
+type NotExpr struct {
+	Operand FilterExpr
+}
+
+// Comparison values are validated against the field type. Patterns of the like operator
+// use "*" as a wildcard and match case-insensitively.
+type Comparison struct {
+	Field  FilterField
+	Op     CompareOp
+	Values []string
+}
+
+func (*LogicalExpr) filterExpr() {}
+func (*NotExpr) filterExpr()     {}
+func (*Comparison) filterExpr()  {}
+
+// ParseFilterExpr parses expressions like "price gt 10 and (color in (red, blue) or not material exists)".
+// Keywords are case-insensitive, values containing spaces or special characters are quoted with ' or ".
+//
+//	expr       = and-expr { "or" and-expr }
+//	and-expr   = unary { "and" unary }
+//	unary      = "not" unary | "(" expr ")" | comparison
+//	comparison = field ( op value | ("in" | "nin") "(" value { "," value } ")" | "exists" )
+func ParseFilterExpr(input string) (FilterExpr, error) {
+	tokens, err := tokenizeFilterExpr(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.errorf("unexpected %s", p.peek())
+	}
+	return expr, nil
+}
+
+type filterTokenKind int
+
+const (
+	tokenWord filterTokenKind = iota
+	tokenQuoted
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type filterToken struct {
+	kind  filterTokenKind
+	value string
+	pos   int
+}
+
+func (t filterToken) String() string {
+	if t.kind == tokenQuoted {
+		return strconv.Quote(t.value)
+	}
+	return "'" + t.value + "'"
+}
+
+func (t filterToken) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.value, keyword)
+}
+
+func tokenizeFilterExpr(input string) ([]filterToken, error) {
+	var tokens []filterToken
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{kind: tokenLParen, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{kind: tokenRParen, value: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, filterToken{kind: tokenComma, value: ",", pos: i})
+			i++
+		case r == '\'' || r == '"':
+			start := i
+			var value strings.Builder
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == r {
+					// doubled quote is an escaped quote
+					if i+1 < len(runes) && runes[i+1] == r {
+						value.WriteRune(r)
+						i++
+						continue
+					}
+					closed = true
+					i++
+					break
+				}
+				value.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, errors.Wrapf(ErrInvalidFilterExpr, "unterminated string at position %d", start)
+			}
+			tokens = append(tokens, filterToken{kind: tokenQuoted, value: value.String(), pos: start})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("(),'\"", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: tokenWord, value: string(runes[start:i]), pos: start})
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() (filterToken, error) {
+	if p.done() {
+		return filterToken{}, errors.Wrap(ErrInvalidFilterExpr, "unexpected end of expression")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *filterParser) expect(kind filterTokenKind, what string) error {
+	t, err := p.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != kind {
+		return errors.Wrapf(ErrInvalidFilterExpr, "expected %s at position %d, got %s", what, t.pos, t)
+	}
+	return nil
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	pos := -1
+	if !p.done() {
+		pos = p.peek().pos
+	}
+	return errors.Wrapf(ErrInvalidFilterExpr, "%s at position %d", fmt.Sprintf(format, args...), pos)
+}
+
+func (p *filterParser) parseOr() (FilterExpr, error) {
+	return p.parseLogical(OpOr, p.parseAnd)
+}
+
+func (p *filterParser) parseAnd() (FilterExpr, error) {
+	return p.parseLogical(OpAnd, p.parseUnary)
+}
+
+func (p *filterParser) parseLogical(op LogicalOp, operand func() (FilterExpr, error)) (FilterExpr, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []FilterExpr{first}
+	for !p.done() && p.peek().isKeyword(string(op)) {
+		p.pos++
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return &LogicalExpr{Op: op, Operands: operands}, nil
+}
+
+func (p *filterParser) parseUnary() (FilterExpr, error) {
+	if p.done() {
+		return nil, errors.Wrap(ErrInvalidFilterExpr, "unexpected end of expression")
+	}
+	t := p.peek()
+	switch {
+	case t.isKeyword("not"):
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotExpr{Operand: operand}, nil
+	case t.kind == tokenLParen:
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(tokenRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (FilterExpr, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	field := FilterField(strings.ToLower(t.value))
+	if _, ok := filterFieldTypes[field]; t.kind != tokenWord || !ok {
+		return nil, errors.Wrapf(ErrInvalidFilterExpr, "unknown field %s at position %d", t, t.pos)
+	}
+	t, err = p.next()
+	if err != nil {
+		return nil, err
+	}
+	op := CompareOp(strings.ToLower(t.value))
+	if t.kind != tokenWord {
+		return nil, errors.Wrapf(ErrInvalidFilterExpr, "expected operator at position %d, got %s", t.pos, t)
+	}
+
+	cmp := &Comparison{Field: field, Op: op}
+	switch op {
+	case OpExists:
+	case OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpLike:
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		cmp.Values = []string{value}
+	case OpIn, OpNin:
+		if cmp.Values, err = p.parseValueList(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Wrapf(ErrInvalidFilterExpr, "unknown operator %s at position %d", t, t.pos)
+	}
+	if err = validateComparison(cmp); err != nil {
+		return nil, errors.Wrapf(ErrInvalidFilterExpr, "%s at position %d", err.Error(), t.pos)
+	}
+	return cmp, nil
+}
+
+func (p *filterParser) parseValue() (string, error) {
+	t, err := p.next()
+	if err != nil {
+		return "", err
+	}
+	if t.kind != tokenWord && t.kind != tokenQuoted {
+		return "", errors.Wrapf(ErrInvalidFilterExpr, "expected value at position %d, got %s", t.pos, t)
+	}
+	return t.value, nil
+}
+
+func (p *filterParser) parseValueList() ([]string, error) {
+	if err := p.expect(tokenLParen, "'('"); err != nil {
+		return nil, err
+	}
+	var values []string
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		t, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind == tokenRParen {
+			return values, nil
+		}
+		if t.kind != tokenComma {
+			return nil, errors.Wrapf(ErrInvalidFilterExpr, "expected ',' or ')' at position %d, got %s", t.pos, t)
+		}
+	}
+}
+
+func validateComparison(cmp *Comparison) error {
+	fieldType := cmp.Field.Type()
+	switch {
+	case cmp.Op == OpLike && fieldType != FilterTypeString:
+		return errors.Errorf("operator 'like' is not supported for field '%s'", cmp.Field)
+	case cmp.Op == OpExists && fieldType != FilterTypeString && fieldType != FilterTypeUUID:
+		return errors.Errorf("operator 'exists' is not supported for field '%s'", cmp.Field)
+	case isOrderingOp(cmp.Op) && fieldType == FilterTypeUUID:
+		return errors.Errorf("operator '%s' is not supported for field '%s'", cmp.Op, cmp.Field)
+	}
+	for _, value := range cmp.Values {
+		var err error
+		switch fieldType {
+		case FilterTypeDecimal:
+			_, err = decimal.NewFromString(value)
+		case FilterTypeInteger:
+			_, err = strconv.Atoi(value)
+		case FilterTypeUUID:
+			_, err = uuid.FromString(value)
+		}
+		if err != nil {
+			return errors.Errorf("invalid value '%s' for field '%s'", value, cmp.Field)
+		}
+	}
+	return nil
+}
+
+func isOrderingOp(op CompareOp) bool {
+	return op == OpGt || op == OpGte || op == OpLt || op == OpLte
+}
@@ -0,0 +1,146 @@
+package application
+
+import (
+	"github.com/pkg/errors"
+)
+
+// importBatchSize limits the number of products written in one transaction
+const importBatchSize = 500
+
+type ImportStatus string
+
+const (
+	ImportStatusCreated ImportStatus = "created"
+	ImportStatusUpdated ImportStatus = "updated"
+	ImportStatusFailed  ImportStatus = "failed"
+)
+
+// ImportRow is a product read from an import file. Rows that can't be parsed or validated carry the error
+// and are reported as failed without being stored.
+type ImportRow struct {
+	// Number is the 1-based position of the row among the data rows of the file
+	Number int
+	SKU    string
+	Params ProductParams
+	Err    error
+}
+
+type ImportRowResult struct {
+	Number int
+	SKU    string
+	ID     *ProductID
+	Status ImportStatus
+	Err    error
+}
+
+type ImportReport struct {
+	Created int
+	Updated int
+	Failed  int
+	Rows    []ImportRowResult
+}
+
+// UpsertResult tells whether the product has been created or updated, or why it has not been stored.
+type UpsertResult struct {
+	Created bool
+	Err     error
+}
+
+// Import creates products with unknown SKUs and replaces the products with known ones. Every batch of rows
+// is stored in its own transaction, a failing row doesn't prevent the others from being stored.
+func (s *service) Import(rows []ImportRow, actor string) (*ImportReport, error) {
+	report := &ImportReport{Rows: make([]ImportRowResult, len(rows))}
+	for start := 0; start < len(rows); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		if err := s.importBatch(rows[start:end], report.Rows[start:end], actor, nil); err != nil {
+			return nil, err
+		}
+	}
+	report.Created, report.Updated, report.Failed = countImportResults(report.Rows)
+	return report, nil
+}
+
+// importBatch stores the rows in one transaction and fills in their results. checkpoint, unless nil, is called
+// with the results before the transaction is committed and the checkpoint it returns is committed with them.
+func (s *service) importBatch(rows []ImportRow, results []ImportRowResult, actor string,
+	checkpoint func(results []ImportRowResult) *ImportCheckpoint) error {
+	var items []*Product
+	var positions []int
+	// rows without the available quantity can only replace existing products
+	withoutQty := make(map[*Product]bool)
+	for i, row := range rows {
+		results[i] = ImportRowResult{Number: row.Number, SKU: row.SKU, Status: ImportStatusFailed, Err: row.Err}
+		if row.Err != nil {
+			continue
+		}
+		item := newProduct(s.repo.NextID(), row.Params)
+		if err := s.normalizeSKU(item); err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].SKU = item.SKU
+		withoutQty[item] = row.Params.GetAvailableQty() == nil
+		items = append(items, item)
+		positions = append(positions, i)
+	}
+	if len(items) == 0 && checkpoint == nil {
+		return nil
+	}
+	prepare := func(current, item *Product) error {
+		if current == nil && withoutQty[item] {
+			return ErrAvailableQtyRequired
+		}
+		return s.prepareImported(current, item)
+	}
+	err := s.repo.Upsert(items, actor, prepare, func(upserted []UpsertResult) *ImportCheckpoint {
+		for j, result := range upserted {
+			i := positions[j]
+			if result.Err != nil {
+				results[i].Err = result.Err
+				continue
+			}
+			id := items[j].ID
+			results[i].ID = &id
+			results[i].Status = ImportStatusUpdated
+			if result.Created {
+				results[i].Status = ImportStatusCreated
+			}
+		}
+		if checkpoint == nil {
+			return nil
+		}
+		return checkpoint(results)
+	})
+	return errors.WithStack(err)
+}
+
+// prepareImported applies the rules of Create or Update to the imported product depending on whether
+// a product with the same SKU exists.
+func (s *service) prepareImported(current, item *Product) error {
+	if current != nil {
+		item.ID = current.ID
+		item.Version = current.Version
+		item.AvailableQty = current.AvailableQty
+		if err := checkVariantAxesChange(current, item); err != nil {
+			return err
+		}
+	}
+	return s.checkVariant(item)
+}
+
+func countImportResults(results []ImportRowResult) (created, updated, failed int) {
+	for _, result := range results {
+		switch result.Status {
+		case ImportStatusCreated:
+			created++
+		case ImportStatusUpdated:
+			updated++
+		default:
+			failed++
+		}
+	}
+	return created, updated, failed
+}
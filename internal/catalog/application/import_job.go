@@ -0,0 +1,113 @@
+package application
+
+import (
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrImportJobNotFound = errors.New("import job not found")
+	// ErrImportJobLost is returned when another worker has taken over the job after the lease had expired
+	ErrImportJobLost = errors.New("import job has been taken over by another worker")
+)
+
+type ImportJobID uuid.UUID
+
+func (u ImportJobID) String() string {
+	return uuid.UUID(u).String()
+}
+
+type ImportJobStatus string
+
+const (
+	ImportJobPending   ImportJobStatus = "pending"
+	ImportJobRunning   ImportJobStatus = "running"
+	ImportJobCompleted ImportJobStatus = "completed"
+	// ImportJobFailed marks jobs whose file can't be read, failed rows don't fail the job
+	ImportJobFailed ImportJobStatus = "failed"
+)
+
+// ImportJob is an import file processed in the background. Rows are counted once their batch is committed.
+type ImportJob struct {
+	ID            ImportJobID
+	Format        string
+	Actor         string
+	Status        ImportJobStatus
+	TotalRows     int
+	ProcessedRows int
+	Created       int
+	Updated       int
+	Failed        int
+	Error         string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	FinishedAt    *time.Time
+}
+
+// ImportCheckpoint is the progress of an import job made by one batch. It is stored in the transaction
+// of the batch, so an interrupted job resumes right after the last committed batch.
+type ImportCheckpoint struct {
+	JobID ImportJobID
+	// From is the number of rows processed before the batch, the checkpoint fails with ErrImportJobLost
+	// when the job has been moved past it by another worker
+	From    int
+	To      int
+	Created int
+	Updated int
+	Failed  int
+	Errors  []ImportRowResult
+	// Lease extends the time the job stays with the worker
+	Lease time.Duration
+}
+
+type ImportJobRepository interface {
+	NextID() ImportJobID
+	Add(job ImportJob, content []byte) error
+	FindByID(id ImportJobID) (*ImportJob, error)
+	// FindErrors returns the failed rows of the job ordered by row number
+	FindErrors(id ImportJobID) ([]ImportRowResult, error)
+	// ClaimNext marks the oldest pending job, or a running job whose lease has expired, as running for
+	// the lease and returns it with its file. It returns nil when there is no job to run.
+	ClaimNext(lease time.Duration) (*ImportJob, []byte, error)
+	// Finish sets the final status of a running job, message tells why the job has failed
+	Finish(id ImportJobID, status ImportJobStatus, message string) error
+}
+
+type ImportJobService interface {
+	// Create stores the file of totalRows rows to be imported on behalf of the actor
+	Create(format string, content []byte, totalRows int, actor string) (*ImportJob, error)
+	FindByID(id uuid.UUID) (*ImportJob, error)
+	FindErrors(id uuid.UUID) ([]ImportRowResult, error)
+}
+
+type importJobService struct {
+	repo ImportJobRepository
+}
+
+func NewImportJobService(repository ImportJobRepository) ImportJobService {
+	return &importJobService{repo: repository}
+}
+
+func (s *importJobService) Create(format string, content []byte, totalRows int, actor string) (*ImportJob, error) {
+	job := ImportJob{
+		ID:        s.repo.NextID(),
+		Format:    format,
+		Actor:     actor,
+		Status:    ImportJobPending,
+		TotalRows: totalRows,
+	}
+	if err := s.repo.Add(job, content); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return s.repo.FindByID(job.ID)
+}
+
+func (s *importJobService) FindByID(id uuid.UUID) (*ImportJob, error) {
+	return s.repo.FindByID(ImportJobID(id))
+}
+
+func (s *importJobService) FindErrors(id uuid.UUID) ([]ImportRowResult, error) {
+	return s.repo.FindErrors(ImportJobID(id))
+}
@@ -0,0 +1,148 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/go-kit/kit/metrics"
+	"github.com/pkg/errors"
+)
+
+// ImportDecoder reads the rows of an import file of the format.
+type ImportDecoder func(format string, content io.Reader) ([]ImportRow, error)
+
+// ImportWorker runs import jobs with a pool of goroutines. Jobs are claimed for a lease that every committed
+// batch extends, a job left by a stopped worker is resumed by any worker once its lease has expired.
+type ImportWorker struct {
+	jobs        ImportJobRepository
+	products    *service
+	decode      ImportDecoder
+	concurrency int
+	interval    time.Duration
+	lease       time.Duration
+	logger      log.Logger
+	// rows counts committed rows by "status": created, updated or failed
+	rows metrics.Counter
+}
+
+func NewImportWorker(
+	jobs ImportJobRepository,
+	products Repository,
+	skuNormalization SKUNormalization,
+	decode ImportDecoder,
+	concurrency int,
+	interval time.Duration,
+	lease time.Duration,
+	logger log.Logger,
+	rows metrics.Counter,
+) *ImportWorker {
+	return &ImportWorker{
+		jobs:        jobs,
+		products:    &service{repo: products, skuNormalization: skuNormalization},
+		decode:      decode,
+		concurrency: concurrency,
+		interval:    interval,
+		lease:       lease,
+		logger:      logger,
+		rows:        rows,
+	}
+}
+
+// Run blocks until ctx is cancelled and the running batches are committed.
+func (w *ImportWorker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.poll(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *ImportWorker) poll(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.runAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ImportWorker) runAll(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, content, err := w.jobs.ClaimNext(w.lease)
+		if err != nil {
+			_ = level.Error(w.logger).Log("msg", "failed to claim import job", "err", err)
+			return
+		}
+		if job == nil {
+			return
+		}
+		w.run(ctx, job, content)
+	}
+}
+
+// run imports the rows of the job left after the last checkpoint. A job interrupted by an error keeps
+// its lease and is resumed after the lease has expired.
+func (w *ImportWorker) run(ctx context.Context, job *ImportJob, content []byte) {
+	rows, err := w.decode(job.Format, bytes.NewReader(content))
+	if err != nil {
+		w.finish(job, ImportJobFailed, err.Error())
+		return
+	}
+	for from := job.ProcessedRows; from < len(rows); from += importBatchSize {
+		if ctx.Err() != nil {
+			return
+		}
+		to := from + importBatchSize
+		if to > len(rows) {
+			to = len(rows)
+		}
+		var checkpoint *ImportCheckpoint
+		record := func(results []ImportRowResult) *ImportCheckpoint {
+			checkpoint = w.checkpoint(job.ID, from, to, results)
+			return checkpoint
+		}
+		err = w.products.importBatch(rows[from:to], make([]ImportRowResult, to-from), job.Actor, record)
+		if errors.Is(err, ErrImportJobLost) {
+			_ = level.Warn(w.logger).Log("msg", "import job taken over by another worker", "job", job.ID)
+			return
+		}
+		if err != nil {
+			_ = level.Error(w.logger).Log("msg", "failed to import batch", "job", job.ID, "from", from, "err", err)
+			return
+		}
+		w.rows.With("status", string(ImportStatusCreated)).Add(float64(checkpoint.Created))
+		w.rows.With("status", string(ImportStatusUpdated)).Add(float64(checkpoint.Updated))
+		w.rows.With("status", string(ImportStatusFailed)).Add(float64(checkpoint.Failed))
+	}
+	w.finish(job, ImportJobCompleted, "")
+}
+
+func (w *ImportWorker) checkpoint(id ImportJobID, from, to int, results []ImportRowResult) *ImportCheckpoint {
+	checkpoint := &ImportCheckpoint{JobID: id, From: from, To: to, Lease: w.lease}
+	checkpoint.Created, checkpoint.Updated, checkpoint.Failed = countImportResults(results)
+	for _, result := range results {
+		if result.Status == ImportStatusFailed {
+			checkpoint.Errors = append(checkpoint.Errors, result)
+		}
+	}
+	return checkpoint
+}
+
+func (w *ImportWorker) finish(job *ImportJob, status ImportJobStatus, message string) {
+	if err := w.jobs.Finish(job.ID, status, message); err != nil {
+		_ = level.Error(w.logger).Log("msg", "failed to finish import job", "job", job.ID, "err", err)
+	}
+}
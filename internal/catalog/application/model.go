@@ -5,6 +5,12 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+const (
+	AxisSize     = "size"
+	AxisColor    = "color"
+	AxisMaterial = "material"
+)
+
 type ProductID uuid.UUID
 
 func (u ProductID) String() string {
@@ -14,6 +20,33 @@ func (u ProductID) String() string {
 type PageSpec struct {
 	Size   int
 	Number int
+	// After switches to keyset pagination, the page starts right after the cursor position
+	After *Cursor
+	// CountTotal requests the total number of items matching the filters
+	CountTotal bool
+	Sort       SortSpec
+}
+
+// Cursor points to the last item of a page: Values holds its sort keys in the order of the listing SortSpec.
+type Cursor struct {
+	Values []string
+	ID     ProductID
+}
+
+type ProductsPage struct {
+	Items   []*Product
+	Next    *Cursor
+	HasNext bool
+	HasPrev bool
+	Total   *int
+}
+
+// ProductBatch holds products in the order they were requested, each product once, and the requested
+// ids and SKUs that matched no product.
+type ProductBatch struct {
+	Items       []*Product
+	MissingIDs  []ProductID
+	MissingSKUs []string
 }
 
 type DecimalRangeFilter struct {
@@ -27,17 +60,48 @@ type Filters struct {
 	Price    DecimalRangeFilter
 	Color    *[]string
 	Material *[]string
+	Category *CategoryFilter
+	Expr     FilterExpr
+	// Warehouse limits products to those stocked at the warehouse, InStock then applies to that warehouse only
+	Warehouse *WarehouseID
+	InStock   *bool
+	// Search is a full-text query in web search syntax
+	Search string
+	// GroupVariants returns parent products instead of their variants
+	GroupVariants bool
+	// Pricing selects the prices returned, filtered and sorted on, products without a price are skipped
+	Pricing *PriceSelection
 }
 
 type Product struct {
-	ID           ProductID
-	Title        string
-	SKU          string
-	Price        decimal.Decimal
+	ID    ProductID
+	Title string
+	SKU   string
+	Price decimal.Decimal
+	// CompareAtPrice is the regular price while a scheduled price is in effect
+	CompareAtPrice *decimal.Decimal
+	// Currency is the ISO 4217 code of the price
+	Currency string
+	// AvailableQty is the total over all warehouses, it is changed by stock movements only
 	AvailableQty int
+	Stock        []WarehouseStock
 	Image        *Image
 	Color        string
 	Material     string
+	Version      int
+	ParentID     *ProductID
+	VariantAxes  []string
+	Options      map[string]string
+	Variants     []*Product
+	PriceRange   *PriceRange
+	// Relevance and Highlight are set only for full-text search results
+	Relevance float64
+	Highlight string
+}
+
+type PriceRange struct {
+	Min decimal.Decimal
+	Max decimal.Decimal
 }
 
 type Image struct {
@@ -46,9 +110,43 @@ type Image struct {
 	Height int
 }
 
+type ProductPatch struct {
+	Title        *string
+	SKU          *string
+	Price        *decimal.Decimal
+	Currency     *string
+	AvailableQty *int
+	ImageURL     *string
+	ImageWidth   *int
+	ImageHeight  *int
+	Color        *string
+	Material     *string
+	VariantAxes  *[]string
+	Options      *map[string]string
+}
+
 type Repository interface {
 	NextID() ProductID
-	FindByID(id ProductID) (*Product, error)
-	Find(spec *PageSpec, filters *Filters) ([]*Product, error)
-	Add(item Product) error
+	// FindByID and FindBySKU read prices of the selection, nil selects the regular product price
+	FindByID(id ProductID, pricing *PriceSelection) (*Product, error)
+	// FindBySKU looks up the product by the normalized SKU
+	FindBySKU(sku string, pricing *PriceSelection) (*Product, error)
+	// FindByIDsOrSKUs returns products matching any of the ids or SKUs in no particular order,
+	// products without a price in the selection are left out
+	FindByIDsOrSKUs(ids []ProductID, skus []string, pricing *PriceSelection) ([]*Product, error)
+	Find(spec *PageSpec, filters *Filters) (*ProductsPage, error)
+	Facets(filters *Filters, spec FacetSpec) (*Facets, error)
+	// Add records the initial stock in the stock ledger, Update keeps the available quantity. Both record
+	// changes of the price in the price history on behalf of the actor
+	Add(item Product, actor string) error
+	Update(item Product, actor string) error
+	// FindPriceHistory returns price changes matching the filter, newest first, also of deleted products
+	FindPriceHistory(filter PriceHistoryFilter, spec *PageSpec) (*PriceChangesPage, error)
+	// Upsert stores the products in one transaction matching existing ones by SKU. prepare is called with
+	// the locked current product, or nil for a new one, before the product is written. Products are written
+	// together, a product failing to be prepared or written is rolled back alone. record is called with
+	// the results before the commit, the import checkpoint it returns, if any, is stored in the same transaction.
+	Upsert(items []*Product, actor string, prepare func(current, item *Product) error,
+		record func(results []UpsertResult) *ImportCheckpoint) error
+	Delete(id ProductID, version int) error
 }
@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/go-kit/kit/metrics"
+)
+
+// OutboxRelay periodically moves events from the outbox to the publisher. Several relays may run
+// at the same time, each batch is locked by one of them.
+type OutboxRelay struct {
+	repo      OutboxRepository
+	publisher EventPublisher
+	interval  time.Duration
+	batchSize int
+	logger    log.Logger
+	published metrics.Counter
+}
+
+func NewOutboxRelay(
+	repository OutboxRepository,
+	publisher EventPublisher,
+	interval time.Duration,
+	batchSize int,
+	logger log.Logger,
+	published metrics.Counter,
+) *OutboxRelay {
+	return &OutboxRelay{
+		repo:      repository,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+		published: published,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		r.publishAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *OutboxRelay) publishAll(ctx context.Context) {
+	for ctx.Err() == nil {
+		count, err := r.repo.PublishPending(r.batchSize, func(events []Event) error {
+			return r.publisher.Publish(ctx, events)
+		})
+		if err != nil {
+			_ = level.Error(r.logger).Log("msg", "failed to publish outbox events", "err", err)
+			return
+		}
+		r.published.Add(float64(count))
+		if count < r.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package application
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PriceChangeReason string
+
+const (
+	// PriceChangeReasonInitial is the price of a new product
+	PriceChangeReasonInitial PriceChangeReason = "initial"
+	// PriceChangeReasonUpdate is a change of the product price or of its price in a price list
+	PriceChangeReasonUpdate PriceChangeReason = "update"
+	// PriceChangeReasonRemoval is a price removed from a price list
+	PriceChangeReasonRemoval PriceChangeReason = "removal"
+	// PriceChangeReasonPriceListDeletion is a price, or a scheduled price, removed with its price list
+	PriceChangeReasonPriceListDeletion PriceChangeReason = "price_list_deletion"
+	// PriceChangeReasonSaleScheduled is a scheduled price in effect from StartsAt until EndsAt
+	PriceChangeReasonSaleScheduled PriceChangeReason = "sale_scheduled"
+	// PriceChangeReasonSaleCancelled is a scheduled price deleted, it is not in effect since the change
+	PriceChangeReasonSaleCancelled PriceChangeReason = "sale_cancelled"
+)
+
+// PriceChange is an append-only record of a change of the product price or of its price in a price list
+// recorded together with the change. Records outlive the product, SKU is the product SKU at the change.
+type PriceChange struct {
+	ID        int64
+	ProductID ProductID
+	SKU       string
+	// PriceListID is nil for the product own price
+	PriceListID *PriceListID
+	Currency    string
+	Reason      PriceChangeReason
+	// OldPrice is nil for the first price, NewPrice is nil for a price removed from the price list
+	OldPrice *decimal.Decimal
+	NewPrice *decimal.Decimal
+	// ScheduledPriceID and the period the scheduled price is in effect are set for sale changes
+	ScheduledPriceID *ScheduledPriceID
+	StartsAt         *time.Time
+	EndsAt           *time.Time
+	Actor            string
+	ChangedAt        time.Time
+}
+
+// PriceHistoryFilter selects price changes of the product, or of the SKU when ProductID is nil, made at From
+// or later and before To, nil bounds are open.
+type PriceHistoryFilter struct {
+	ProductID *ProductID
+	SKU       string
+	From      *time.Time
+	To        *time.Time
+}
+
+type PriceChangesPage struct {
+	Items   []*PriceChange
+	HasNext bool
+	HasPrev bool
+}
@@ -0,0 +1,72 @@
+package application
+
+import (
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultCurrency is the currency of products created without one.
+const DefaultCurrency = "USD"
+
+type PriceListID uuid.UUID
+
+func (u PriceListID) String() string {
+	return uuid.UUID(u).String()
+}
+
+// PriceList holds product prices in one currency for a market and a customer group. The list without
+// market and customer group is the default list of its currency.
+type PriceList struct {
+	ID            PriceListID
+	Name          string
+	Currency      string
+	Market        string
+	CustomerGroup string
+}
+
+// PriceSelection selects the prices of products read: the entries of the price list, or of the default list
+// of the currency when no list is given. Products priced in the selected currency fall back to their own price,
+// the others have no price in the selection. Without currency and list products keep their own price.
+// Scheduled prices in effect at the time At replace the regular ones.
+type PriceSelection struct {
+	Currency    string
+	PriceListID *PriceListID
+	At          time.Time
+}
+
+type ScheduledPriceID uuid.UUID
+
+func (u ScheduledPriceID) String() string {
+	return uuid.UUID(u).String()
+}
+
+// ScheduledPrice replaces the regular price of the product, or of its entry in the price list, from StartsAt
+// until EndsAt, nil EndsAt never ends. The latest started one wins when they overlap.
+type ScheduledPrice struct {
+	ID          ScheduledPriceID
+	ProductID   ProductID
+	PriceListID *PriceListID
+	Price       decimal.Decimal
+	StartsAt    time.Time
+	EndsAt      *time.Time
+}
+
+type PriceListRepository interface {
+	NextID() PriceListID
+	FindByID(id PriceListID) (*PriceList, error)
+	FindAll() ([]*PriceList, error)
+	Add(item PriceList) error
+	// Delete records removal of the prices of the price list in the price history on behalf of the actor
+	Delete(id PriceListID, actor string) error
+	// SetPrice and DeletePrice record the change in the price history on behalf of the actor
+	SetPrice(id PriceListID, productID ProductID, price decimal.Decimal, actor string) error
+	DeletePrice(id PriceListID, productID ProductID, actor string) error
+	NextScheduledPriceID() ScheduledPriceID
+	// FindScheduledPrices returns scheduled prices of the product ordered by start
+	FindScheduledPrices(productID ProductID) ([]*ScheduledPrice, error)
+	// AddScheduledPrice and DeleteScheduledPrice record the sale in the price history on behalf of the actor
+	AddScheduledPrice(item ScheduledPrice, actor string) error
+	DeleteScheduledPrice(productID ProductID, id ScheduledPriceID, actor string) error
+}
@@ -0,0 +1,151 @@
+package application
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrPriceListNotFound  = errors.New("price list not found")
+	ErrDuplicatePriceList = errors.New("price list for such currency, market and customer group already exists")
+	ErrPriceNotFound      = errors.New("product has no price in the selected price list")
+	ErrInvalidCurrency    = errors.New("invalid currency")
+
+	ErrScheduledPriceNotFound = errors.New("scheduled price not found")
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ParseCurrency checks the ISO 4217 code of a currency, lower case codes are accepted.
+func ParseCurrency(code string) (string, error) {
+	currency := strings.ToUpper(code)
+	if !currencyPattern.MatchString(currency) {
+		return "", errors.Wrapf(ErrInvalidCurrency, "'%s' is not an ISO 4217 currency code", code)
+	}
+	return currency, nil
+}
+
+type PriceListParams interface {
+	GetName() string
+	GetCurrency() string
+	GetMarket() string
+	GetCustomerGroup() string
+}
+
+type ScheduledPriceParams interface {
+	GetPriceListID() *uuid.UUID
+	GetPrice() decimal.Decimal
+	GetStartsAt() time.Time
+	GetEndsAt() *time.Time
+}
+
+type PriceListService interface {
+	FindByID(id uuid.UUID) (*PriceList, error)
+	FindAll() ([]*PriceList, error)
+	Create(params PriceListParams) (PriceListID, error)
+	// Delete removes the price list with all its prices
+	Delete(id uuid.UUID, actor string) error
+	// SetPrice sets the price of the product in the currency of the price list
+	SetPrice(id uuid.UUID, productID uuid.UUID, price decimal.Decimal, actor string) error
+	DeletePrice(id uuid.UUID, productID uuid.UUID, actor string) error
+	FindScheduledPrices(productID uuid.UUID) ([]*ScheduledPrice, error)
+	// SchedulePrice adds a price of the product in effect for a period
+	SchedulePrice(productID uuid.UUID, params ScheduledPriceParams, actor string) (ScheduledPriceID, error)
+	DeleteScheduledPrice(productID uuid.UUID, id uuid.UUID, actor string) error
+}
+
+type priceListService struct {
+	repo PriceListRepository
+}
+
+func NewPriceListService(repository PriceListRepository) PriceListService {
+	return &priceListService{repo: repository}
+}
+
+func (s *priceListService) FindByID(id uuid.UUID) (*PriceList, error) {
+	return s.repo.FindByID(PriceListID(id))
+}
+
+func (s *priceListService) FindAll() ([]*PriceList, error) {
+	return s.repo.FindAll()
+}
+
+func (s *priceListService) Create(params PriceListParams) (PriceListID, error) {
+	item := &PriceList{
+		ID:            s.repo.NextID(),
+		Name:          params.GetName(),
+		Currency:      params.GetCurrency(),
+		Market:        params.GetMarket(),
+		CustomerGroup: params.GetCustomerGroup(),
+	}
+	if err := s.repo.Add(*item); err != nil {
+		return PriceListID{}, errors.WithStack(err)
+	}
+	return item.ID, nil
+}
+
+func (s *priceListService) Delete(id uuid.UUID, actor string) error {
+	return s.repo.Delete(PriceListID(id), actor)
+}
+
+func (s *priceListService) SetPrice(id uuid.UUID, productID uuid.UUID, price decimal.Decimal, actor string) error {
+	return s.repo.SetPrice(PriceListID(id), ProductID(productID), price, actor)
+}
+
+func (s *priceListService) DeletePrice(id uuid.UUID, productID uuid.UUID, actor string) error {
+	return s.repo.DeletePrice(PriceListID(id), ProductID(productID), actor)
+}
+
+func (s *priceListService) FindScheduledPrices(productID uuid.UUID) ([]*ScheduledPrice, error) {
+	return s.repo.FindScheduledPrices(ProductID(productID))
+}
+
+func (s *priceListService) SchedulePrice(productID uuid.UUID, params ScheduledPriceParams, actor string) (ScheduledPriceID, error) {
+	item := &ScheduledPrice{
+		ID:        s.repo.NextScheduledPriceID(),
+		ProductID: ProductID(productID),
+		Price:     params.GetPrice(),
+		StartsAt:  params.GetStartsAt(),
+		EndsAt:    params.GetEndsAt(),
+	}
+	if listID := params.GetPriceListID(); listID != nil {
+		id := PriceListID(*listID)
+		item.PriceListID = &id
+	}
+	if err := s.repo.AddScheduledPrice(*item, actor); err != nil {
+		return ScheduledPriceID{}, errors.WithStack(err)
+	}
+	return item.ID, nil
+}
+
+func (s *priceListService) DeleteScheduledPrice(productID uuid.UUID, id uuid.UUID, actor string) error {
+	return s.repo.DeleteScheduledPrice(ProductID(productID), ScheduledPriceID(id), actor)
+}
+
+// resolvePriceSelection completes the selection with the currency of the selected price list, prices are
+// resolved at the current time unless another time is selected.
+func resolvePriceSelection(repo PriceListRepository, selection *PriceSelection) error {
+	if selection == nil {
+		return nil
+	}
+	if selection.At.IsZero() {
+		selection.At = time.Now()
+	}
+	if selection.PriceListID == nil {
+		return nil
+	}
+	list, err := repo.FindByID(*selection.PriceListID)
+	if err != nil {
+		return err
+	}
+	if selection.Currency != "" && selection.Currency != list.Currency {
+		return errors.Wrapf(ErrInvalidCurrency, "price list is in %s, not in %s", list.Currency, selection.Currency)
+	}
+	selection.Currency = list.Currency
+	return nil
+}
@@ -0,0 +1,67 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/go-kit/kit/metrics"
+)
+
+// ReservationExpiryWorker periodically releases pending reservations past their TTL. Rows are claimed
+// with SKIP LOCKED, so the worker may run on every replica at the same time.
+type ReservationExpiryWorker struct {
+	repo     ReservationRepository
+	interval time.Duration
+	logger   log.Logger
+	// expired counts expired reservations, returned counts units of stock returned to products
+	expired  metrics.Counter
+	returned metrics.Counter
+}
+
+func NewReservationExpiryWorker(
+	repository ReservationRepository,
+	interval time.Duration,
+	logger log.Logger,
+	expired, returned metrics.Counter,
+) *ReservationExpiryWorker {
+	return &ReservationExpiryWorker{
+		repo:     repository,
+		interval: interval,
+		logger:   logger,
+		expired:  expired,
+		returned: returned,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *ReservationExpiryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.expireAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ReservationExpiryWorker) expireAll(ctx context.Context) {
+	for ctx.Err() == nil {
+		item, err := w.repo.ExpireNext()
+		if err != nil {
+			_ = level.Error(w.logger).Log("msg", "failed to expire reservation", "err", err)
+			return
+		}
+		if item == nil {
+			return
+		}
+		w.expired.Add(1)
+		for _, reserved := range item.Items {
+			w.returned.Add(float64(reserved.Quantity))
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package application
+
+import (
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+)
+
+type ReservationID uuid.UUID
+
+func (u ReservationID) String() string {
+	return uuid.UUID(u).String()
+}
+
+type ReservationStatus string
+
+const (
+	ReservationPending   ReservationStatus = "pending"
+	ReservationConfirmed ReservationStatus = "confirmed"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+// Reservation holds stock of its items until it is confirmed or released. Pending reservations
+// can't be confirmed after ExpiresAt.
+type Reservation struct {
+	ID        ReservationID
+	Status    ReservationStatus
+	Items     []ReservationItem
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// ReservationItem without WarehouseID is allocated from any warehouses with enough stock,
+// stored reservations always hold one item per product and warehouse.
+type ReservationItem struct {
+	ProductID   ProductID
+	WarehouseID *WarehouseID
+	Quantity    int
+}
+
+type ReservationRepository interface {
+	NextID() ReservationID
+	FindByID(id ReservationID) (*Reservation, error)
+	// Add allocates items to warehouses, decrements their available quantity and stores the reservation
+	// in one transaction, items of the reservation are replaced with the allocations
+	Add(item *Reservation) error
+	Confirm(id ReservationID) error
+	// Release returns reserved quantity back to the products
+	Release(id ReservationID) error
+	// ExpireNext releases one pending reservation past its TTL that is not locked by another
+	// transaction and marks it expired, it returns nil when there is nothing to expire
+	ExpireNext() (*Reservation, error)
+}
@@ -0,0 +1,113 @@
+package application
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInsufficientStock   = errors.New("insufficient stock")
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationClosed   = errors.New("reservation is already confirmed, released or expired")
+)
+
+type ReservationItemParams interface {
+	GetProductID() uuid.UUID
+	// GetWarehouseID returns nil to allocate the quantity from any warehouses
+	GetWarehouseID() *uuid.UUID
+	GetQuantity() int
+}
+
+type ReservationParams interface {
+	GetItems() []ReservationItemParams
+	// GetTTL returns zero to use the default TTL
+	GetTTL() time.Duration
+}
+
+type ReservationService interface {
+	FindByID(id uuid.UUID) (*Reservation, error)
+	Reserve(params ReservationParams) (*Reservation, error)
+	Confirm(id uuid.UUID) (*Reservation, error)
+	Release(id uuid.UUID) (*Reservation, error)
+}
+
+type reservationService struct {
+	repo       ReservationRepository
+	defaultTTL time.Duration
+}
+
+func NewReservationService(repository ReservationRepository, defaultTTL time.Duration) ReservationService {
+	return &reservationService{repo: repository, defaultTTL: defaultTTL}
+}
+
+func (s *reservationService) FindByID(id uuid.UUID) (*Reservation, error) {
+	return s.repo.FindByID(ReservationID(id))
+}
+
+func (s *reservationService) Reserve(params ReservationParams) (*Reservation, error) {
+	ttl := params.GetTTL()
+	if ttl <= 0 {
+		ttl = s.defaultTTL
+	}
+	now := time.Now()
+	item := &Reservation{
+		ID:        s.repo.NextID(),
+		Status:    ReservationPending,
+		Items:     mergeReservationItems(params.GetItems()),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := s.repo.Add(item); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return item, nil
+}
+
+func (s *reservationService) Confirm(id uuid.UUID) (*Reservation, error) {
+	if err := s.repo.Confirm(ReservationID(id)); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ReservationID(id))
+}
+
+func (s *reservationService) Release(id uuid.UUID) (*Reservation, error) {
+	if err := s.repo.Release(ReservationID(id)); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ReservationID(id))
+}
+
+// mergeReservationItems sums quantities of the same product and warehouse and orders items by product id.
+func mergeReservationItems(params []ReservationItemParams) []ReservationItem {
+	type key struct {
+		productID   ProductID
+		warehouseID WarehouseID
+		any         bool
+	}
+	indexes := make(map[key]int, len(params))
+	var items []ReservationItem
+	for _, p := range params {
+		item := ReservationItem{
+			ProductID:   ProductID(p.GetProductID()),
+			WarehouseID: warehouseID(p.GetWarehouseID()),
+			Quantity:    p.GetQuantity(),
+		}
+		k := key{productID: item.ProductID, any: item.WarehouseID == nil}
+		if item.WarehouseID != nil {
+			k.warehouseID = *item.WarehouseID
+		}
+		if i, ok := indexes[k]; ok {
+			items[i].Quantity += item.Quantity
+			continue
+		}
+		indexes[k] = len(items)
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ProductID.String() < items[j].ProductID.String()
+	})
+	return items
+}
@@ -9,61 +9,415 @@ import (
 var (
 	ErrProductNotFound  = errors.New("product not found")
 	ErrDuplicateProduct = errors.New("product with such SKU already exists")
+	ErrVersionMismatch  = errors.New("product has been modified by another request")
+	ErrInvalidVariant   = errors.New("invalid product variant")
+	// ErrAvailableQtyChange is returned for a patch of the available quantity, the total over warehouses
+	// is changed by stock adjustments
+	ErrAvailableQtyChange = errors.New("available quantity can't be patched, adjust stock at a warehouse instead")
+	// ErrAvailableQtyRequired is returned for a new product without the initial available quantity
+	ErrAvailableQtyRequired = errors.New("available quantity is required for a new product")
 )
 
+// exportPageSize is the number of products read at once by exports
+const exportPageSize = 500
+
 type ProductParams interface {
 	GetTitle() string
 	GetSKU() string
 	GetPrice() decimal.Decimal
-	GetAvailableQty() int
+	GetCurrency() string
+	// GetAvailableQty is the initial stock of a new product, nil when not given. Updates ignore it.
+	GetAvailableQty() *int
 	GetImageURL() string
 	GetImageWidth() int
 	GetImageHeight() int
 	GetColor() string
 	GetMaterial() string
+	GetParentID() *uuid.UUID
+	GetVariantAxes() []string
+	GetOptions() map[string]string
 }
 
 type Service interface {
-	FindByID(id uuid.UUID) (*Product, error)
-	Find(spec *PageSpec, filters *Filters) ([]*Product, error)
-	Create(params ProductParams) (ProductID, error)
+	// FindByID and FindBySKU return the price of the selection, nil selects the regular product price
+	FindByID(id uuid.UUID, pricing *PriceSelection) (*Product, error)
+	FindBySKU(sku string, pricing *PriceSelection) (*Product, error)
+	// FindBatch looks up products by ids and SKUs at once, missing ones are reported in the batch
+	FindBatch(ids []uuid.UUID, skus []string, pricing *PriceSelection) (*ProductBatch, error)
+	Find(spec *PageSpec, filters *Filters) (*ProductsPage, error)
+	Facets(filters *Filters, spec FacetSpec) (*Facets, error)
+	// Export passes the products matching the filters to write page by page in the sort order,
+	// only one page is held in memory at a time
+	Export(filters *Filters, sort SortSpec, write func(items []*Product) error) error
+	Create(params ProductParams, actor string) (ProductID, error)
+	Update(id uuid.UUID, versions []int, params ProductParams, actor string) (*Product, error)
+	Patch(id uuid.UUID, versions []int, patch ProductPatch, actor string) (*Product, error)
+	Delete(id uuid.UUID, versions []int) error
+	// PriceHistory returns changes of the product own price and of its prices in price lists, newest first
+	PriceHistory(id uuid.UUID, filter PriceHistoryFilter, spec *PageSpec) (*PriceChangesPage, error)
+	// PriceHistoryBySKU returns price changes of products having had the SKU, deleted ones too
+	PriceHistoryBySKU(sku string, filter PriceHistoryFilter, spec *PageSpec) (*PriceChangesPage, error)
+	// Import upserts products by SKU on behalf of the actor and reports the outcome of every row
+	Import(rows []ImportRow, actor string) (*ImportReport, error)
 }
 
 type service struct {
-	repo Repository
+	repo             Repository
+	priceLists       PriceListRepository
+	skuNormalization SKUNormalization
 }
 
-func NewService(repository Repository) Service {
-	return &service{repo: repository}
+func NewService(repository Repository, priceLists PriceListRepository, skuNormalization SKUNormalization) Service {
+	return &service{repo: repository, priceLists: priceLists, skuNormalization: skuNormalization}
 }
 
-func (s *service) FindByID(id uuid.UUID) (*Product, error) {
-	return s.repo.FindByID(ProductID(id))
+func (s *service) FindByID(id uuid.UUID, pricing *PriceSelection) (*Product, error) {
+	if err := resolvePriceSelection(s.priceLists, pricing); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ProductID(id), pricing)
 }
 
-func (s *service) Find(spec *PageSpec, filters *Filters) ([]*Product, error) {
+func (s *service) FindBySKU(sku string, pricing *PriceSelection) (*Product, error) {
+	if err := resolvePriceSelection(s.priceLists, pricing); err != nil {
+		return nil, err
+	}
+	return s.repo.FindBySKU(s.skuNormalization.Normalize(sku), pricing)
+}
+
+func (s *service) FindBatch(ids []uuid.UUID, skus []string, pricing *PriceSelection) (*ProductBatch, error) {
+	if err := resolvePriceSelection(s.priceLists, pricing); err != nil {
+		return nil, err
+	}
+	productIDs := make([]ProductID, len(ids))
+	for i, id := range ids {
+		productIDs[i] = ProductID(id)
+	}
+	normalizedSKUs := make([]string, len(skus))
+	for i, sku := range skus {
+		normalizedSKUs[i] = s.skuNormalization.Normalize(sku)
+	}
+	items, err := s.repo.FindByIDsOrSKUs(productIDs, normalizedSKUs, pricing)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[ProductID]*Product, len(items))
+	bySKU := make(map[string]*Product, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+		bySKU[item.SKU] = item
+	}
+
+	batch := &ProductBatch{}
+	added := make(map[ProductID]bool, len(items))
+	missingIDs := make(map[ProductID]bool)
+	missingSKUs := make(map[string]bool)
+	for _, id := range productIDs {
+		item, ok := byID[id]
+		if ok && !added[id] {
+			added[id] = true
+			batch.Items = append(batch.Items, item)
+		} else if !ok && !missingIDs[id] {
+			missingIDs[id] = true
+			batch.MissingIDs = append(batch.MissingIDs, id)
+		}
+	}
+	for i, sku := range skus {
+		item, ok := bySKU[normalizedSKUs[i]]
+		if ok && !added[item.ID] {
+			added[item.ID] = true
+			batch.Items = append(batch.Items, item)
+		} else if !ok && !missingSKUs[sku] {
+			missingSKUs[sku] = true
+			batch.MissingSKUs = append(batch.MissingSKUs, sku)
+		}
+	}
+	return batch, nil
+}
+
+func (s *service) Find(spec *PageSpec, filters *Filters) (*ProductsPage, error) {
+	if err := s.resolveFilters(filters); err != nil {
+		return nil, err
+	}
 	return s.repo.Find(spec, filters)
 }
 
-func (s *service) Create(params ProductParams) (ProductID, error) {
+func (s *service) Facets(filters *Filters, spec FacetSpec) (*Facets, error) {
+	if err := s.resolveFilters(filters); err != nil {
+		return nil, err
+	}
+	return s.repo.Facets(filters, spec)
+}
+
+func (s *service) Export(filters *Filters, sort SortSpec, write func(items []*Product) error) error {
+	if err := s.resolveFilters(filters); err != nil {
+		return err
+	}
+	spec := &PageSpec{Size: exportPageSize, Number: 1, Sort: sort}
+	for {
+		page, err := s.repo.Find(spec, filters)
+		if err != nil {
+			return err
+		}
+		if len(page.Items) > 0 {
+			if err = write(page.Items); err != nil {
+				return err
+			}
+		}
+		if !page.HasNext {
+			return nil
+		}
+		spec.After = page.Next
+	}
+}
+
+func (s *service) Create(params ProductParams, actor string) (ProductID, error) {
+	if params.GetAvailableQty() == nil {
+		return ProductID{}, ErrAvailableQtyRequired
+	}
 	id := s.repo.NextID()
-	item := Product{
-		ID:           id,
-		Title:        params.GetTitle(),
-		SKU:          params.GetSKU(),
-		Price:        params.GetPrice(),
-		AvailableQty: params.GetAvailableQty(),
+	item := newProduct(id, params)
+	if err := s.normalizeSKU(item); err != nil {
+		return ProductID{}, err
+	}
+	if err := s.checkVariant(item); err != nil {
+		return ProductID{}, err
+	}
+	err := s.repo.Add(*item, actor)
+	if err != nil {
+		return ProductID{}, errors.WithStack(err)
+	}
+	return id, nil
+}
+
+func (s *service) Update(id uuid.UUID, versions []int, params ProductParams, actor string) (*Product, error) {
+	current, err := s.findVersioned(id, versions)
+	if err != nil {
+		return nil, err
+	}
+	item := newProduct(current.ID, params)
+	item.Version = current.Version
+	// the available quantity is kept, it is the total over warehouses
+	item.AvailableQty = current.AvailableQty
+	if err = s.normalizeSKU(item); err != nil {
+		return nil, err
+	}
+	if err = checkVariantAxesChange(current, item); err != nil {
+		return nil, err
+	}
+	if err = s.checkVariant(item); err != nil {
+		return nil, err
+	}
+	if err = s.repo.Update(*item, actor); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	item.Version++
+	return item, nil
+}
+
+func (s *service) Patch(id uuid.UUID, versions []int, patch ProductPatch, actor string) (*Product, error) {
+	if patch.AvailableQty != nil {
+		return nil, ErrAvailableQtyChange
+	}
+	item, err := s.findVersioned(id, versions)
+	if err != nil {
+		return nil, err
+	}
+	current := *item
+	applyPatch(item, patch)
+	if patch.SKU != nil {
+		if err = s.normalizeSKU(item); err != nil {
+			return nil, err
+		}
+	}
+	if err = checkVariantAxesChange(&current, item); err != nil {
+		return nil, err
+	}
+	if err = s.checkVariant(item); err != nil {
+		return nil, err
+	}
+	if err = s.repo.Update(*item, actor); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	item.Version++
+	return item, nil
+}
+
+func (s *service) Delete(id uuid.UUID, versions []int) error {
+	item, err := s.findVersioned(id, versions)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(item.ID, item.Version)
+}
+
+func (s *service) PriceHistory(id uuid.UUID, filter PriceHistoryFilter, spec *PageSpec) (*PriceChangesPage, error) {
+	productID := ProductID(id)
+	filter.ProductID = &productID
+	return s.repo.FindPriceHistory(filter, spec)
+}
+
+func (s *service) PriceHistoryBySKU(sku string, filter PriceHistoryFilter, spec *PageSpec) (*PriceChangesPage, error) {
+	filter.ProductID = nil
+	filter.SKU = s.skuNormalization.Normalize(sku)
+	return s.repo.FindPriceHistory(filter, spec)
+}
+
+func (s *service) resolveFilters(filters *Filters) error {
+	if filters == nil {
+		return nil
+	}
+	return resolvePriceSelection(s.priceLists, filters.Pricing)
+}
+
+// findVersioned loads the product and checks it against the versions expected by the caller,
+// nil versions match any.
+func (s *service) findVersioned(id uuid.UUID, versions []int) (*Product, error) {
+	item, err := s.repo.FindByID(ProductID(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	if versions == nil {
+		return item, nil
+	}
+	for _, version := range versions {
+		if version == item.Version {
+			return item, nil
+		}
+	}
+	return nil, ErrVersionMismatch
+}
+
+func (s *service) normalizeSKU(item *Product) error {
+	item.SKU = s.skuNormalization.Normalize(item.SKU)
+	if item.SKU == "" {
+		return errors.Wrap(ErrInvalidSKU, "SKU is empty after normalization")
+	}
+	return nil
+}
+
+// checkVariant validates the link between a variant and its parent and copies the color
+// and material options into the dedicated attributes so that they can be filtered on.
+func (s *service) checkVariant(item *Product) error {
+	if item.ParentID == nil {
+		if len(item.Options) > 0 {
+			return errors.Wrap(ErrInvalidVariant, "options are allowed only for variants")
+		}
+		return nil
+	}
+	if len(item.VariantAxes) > 0 {
+		return errors.Wrap(ErrInvalidVariant, "variant can't define variant axes")
+	}
+	if *item.ParentID == item.ID {
+		return errors.Wrap(ErrInvalidVariant, "product can't be a variant of itself")
+	}
+	parent, err := s.repo.FindByID(*item.ParentID, nil)
+	if err != nil {
+		if errors.Is(err, ErrProductNotFound) {
+			return errors.Wrap(ErrInvalidVariant, "parent product not found")
+		}
+		return err
+	}
+	if parent.ParentID != nil {
+		return errors.Wrap(ErrInvalidVariant, "parent product is a variant itself")
+	}
+	if len(item.Options) != len(parent.VariantAxes) {
+		return errors.Wrap(ErrInvalidVariant, "options must match variant axes of the parent product")
+	}
+	for _, axis := range parent.VariantAxes {
+		if item.Options[axis] == "" {
+			return errors.Wrapf(ErrInvalidVariant, "missing option '%s'", axis)
+		}
+	}
+	if color, ok := item.Options[AxisColor]; ok {
+		item.Color = color
+	}
+	if material, ok := item.Options[AxisMaterial]; ok {
+		item.Material = material
+	}
+	return nil
+}
+
+func checkVariantAxesChange(current, item *Product) error {
+	if len(current.Variants) == 0 {
+		return nil
+	}
+	if item.ParentID != nil {
+		return errors.Wrap(ErrInvalidVariant, "product with variants can't become a variant")
+	}
+	if len(current.VariantAxes) != len(item.VariantAxes) {
+		return errors.Wrap(ErrInvalidVariant, "variant axes can't be changed while product has variants")
+	}
+	for i, axis := range current.VariantAxes {
+		if item.VariantAxes[i] != axis {
+			return errors.Wrap(ErrInvalidVariant, "variant axes can't be changed while product has variants")
+		}
+	}
+	return nil
+}
+
+func newProduct(id ProductID, params ProductParams) *Product {
+	item := &Product{
+		ID:       id,
+		Title:    params.GetTitle(),
+		SKU:      params.GetSKU(),
+		Price:    params.GetPrice(),
+		Currency: params.GetCurrency(),
 		Image: &Image{
 			URL:    params.GetImageURL(),
 			Width:  params.GetImageWidth(),
 			Height: params.GetImageHeight(),
 		},
-		Color:    params.GetColor(),
-		Material: params.GetMaterial(),
+		Color:       params.GetColor(),
+		Material:    params.GetMaterial(),
+		VariantAxes: params.GetVariantAxes(),
+		Options:     params.GetOptions(),
 	}
-	err := s.repo.Add(item)
-	if err != nil {
-		return ProductID{}, errors.WithStack(err)
+	if qty := params.GetAvailableQty(); qty != nil {
+		item.AvailableQty = *qty
+	}
+	if item.Currency == "" {
+		item.Currency = DefaultCurrency
+	}
+	if parentID := params.GetParentID(); parentID != nil {
+		productID := ProductID(*parentID)
+		item.ParentID = &productID
+	}
+	return item
+}
+
+func applyPatch(item *Product, patch ProductPatch) {
+	if patch.Title != nil {
+		item.Title = *patch.Title
+	}
+	if patch.SKU != nil {
+		item.SKU = *patch.SKU
+	}
+	if patch.Price != nil {
+		item.Price = *patch.Price
+	}
+	if patch.Currency != nil {
+		item.Currency = *patch.Currency
+	}
+	if patch.ImageURL != nil {
+		item.Image.URL = *patch.ImageURL
+	}
+	if patch.ImageWidth != nil {
+		item.Image.Width = *patch.ImageWidth
+	}
+	if patch.ImageHeight != nil {
+		item.Image.Height = *patch.ImageHeight
+	}
+	if patch.Color != nil {
+		item.Color = *patch.Color
+	}
+	if patch.Material != nil {
+		item.Material = *patch.Material
+	}
+	if patch.VariantAxes != nil {
+		item.VariantAxes = *patch.VariantAxes
+	}
+	if patch.Options != nil {
+		item.Options = *patch.Options
 	}
-	return id, nil
 }
@@ -0,0 +1,45 @@
+package application
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidSKU = errors.New("invalid SKU")
+
+type SKUCase string
+
+const (
+	SKUCasePreserve SKUCase = "preserve"
+	SKUCaseUpper    SKUCase = "upper"
+	SKUCaseLower    SKUCase = "lower"
+)
+
+func ParseSKUCase(s string) (SKUCase, error) {
+	switch c := SKUCase(s); c {
+	case SKUCasePreserve, SKUCaseUpper, SKUCaseLower:
+		return c, nil
+	}
+	return "", errors.Errorf("unsupported SKU case '%s'", s)
+}
+
+// SKUNormalization rewrites SKUs on writes and lookups, so SKUs differing only in the normalized parts
+// are the same SKU for the unique constraint. SKUs stored before the rules change are not rewritten.
+type SKUNormalization struct {
+	TrimSpace bool
+	Case      SKUCase
+}
+
+func (n SKUNormalization) Normalize(sku string) string {
+	if n.TrimSpace {
+		sku = strings.TrimSpace(sku)
+	}
+	switch n.Case {
+	case SKUCaseUpper:
+		sku = strings.ToUpper(sku)
+	case SKUCaseLower:
+		sku = strings.ToLower(sku)
+	}
+	return sku
+}
@@ -0,0 +1,64 @@
+package application
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type SortField string
+
+const (
+	SortByTitle        SortField = "title"
+	SortBySKU          SortField = "sku"
+	SortByPrice        SortField = "price"
+	SortByAvailableQty SortField = "available_qty"
+	// SortByRelevance is available only for full-text search
+	SortByRelevance SortField = "relevance"
+)
+
+var ErrUnsupportedSortField = errors.New("unsupported sort field")
+
+var sortableFields = map[SortField]bool{
+	SortByTitle:        true,
+	SortBySKU:          true,
+	SortByPrice:        true,
+	SortByAvailableQty: true,
+	SortByRelevance:    true,
+}
+
+type SortOrder struct {
+	Field SortField
+	Desc  bool
+}
+
+// SortSpec lists sort keys by priority, ties are always broken by product id.
+type SortSpec []SortOrder
+
+func NewSortOrder(field string, desc bool) (SortOrder, error) {
+	f := SortField(field)
+	if !sortableFields[f] {
+		return SortOrder{}, errors.Wrapf(ErrUnsupportedSortField, "'%s'", field)
+	}
+	return SortOrder{Field: f, Desc: desc}, nil
+}
+
+func (s SortSpec) Contains(field SortField) bool {
+	for _, order := range s {
+		if order.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (s SortSpec) String() string {
+	parts := make([]string, len(s))
+	for i, order := range s {
+		parts[i] = string(order.Field)
+		if order.Desc {
+			parts[i] = "-" + parts[i]
+		}
+	}
+	return strings.Join(parts, ",")
+}
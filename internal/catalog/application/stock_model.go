@@ -0,0 +1,65 @@
+package application
+
+import (
+	"time"
+)
+
+type StockReason string
+
+const (
+	StockReasonReceipt    StockReason = "receipt"
+	StockReasonSale       StockReason = "sale"
+	StockReasonReturn     StockReason = "return"
+	StockReasonDamage     StockReason = "damage"
+	StockReasonCorrection StockReason = "correction"
+
+	// reasons recorded by the service itself
+	StockReasonInitial            StockReason = "initial"
+	StockReasonReservation        StockReason = "reservation"
+	StockReasonReservationRelease StockReason = "reservation_release"
+	StockReasonReservationExpiry  StockReason = "reservation_expiry"
+)
+
+var adjustmentReasons = map[StockReason]bool{
+	StockReasonReceipt:    true,
+	StockReasonSale:       true,
+	StockReasonReturn:     true,
+	StockReasonDamage:     true,
+	StockReasonCorrection: true,
+}
+
+// IsAdjustment reports whether the reason can be used for a manual stock adjustment.
+func (r StockReason) IsAdjustment() bool {
+	return adjustmentReasons[r]
+}
+
+// StockMovement is an append-only ledger entry recorded right after the stock change.
+type StockMovement struct {
+	ID        int64
+	ProductID ProductID
+	// WarehouseID is nil for the default warehouse until the movement is stored
+	WarehouseID *WarehouseID
+	Delta       int
+	// Balance is the total available quantity of the product over all warehouses
+	Balance int
+	Reason  StockReason
+	Actor   string
+	// Reference points to the source of the movement, e.g. a reservation id
+	Reference string
+	Note      string
+	CreatedAt time.Time
+}
+
+type StockMovementsPage struct {
+	Items   []*StockMovement
+	HasNext bool
+	HasPrev bool
+}
+
+type StockRepository interface {
+	// Add applies the movement delta to the product quantity and appends the movement to the ledger,
+	// Balance and ID of the movement are filled in
+	Add(item *StockMovement) error
+	// FindByProduct returns movements of the product, newest first
+	FindByProduct(productID ProductID, spec *PageSpec) (*StockMovementsPage, error)
+}
@@ -0,0 +1,65 @@
+package application
+
+import (
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidStockAdjustment = errors.New("invalid stock adjustment")
+
+type StockAdjustmentParams interface {
+	// GetWarehouseID returns nil for the default warehouse
+	GetWarehouseID() *uuid.UUID
+	GetDelta() int
+	GetReason() StockReason
+	GetNote() string
+}
+
+type StockService interface {
+	Adjust(productID uuid.UUID, params StockAdjustmentParams, actor string) (*StockMovement, error)
+	Movements(productID uuid.UUID, spec *PageSpec) (*StockMovementsPage, error)
+}
+
+type stockService struct {
+	repo StockRepository
+}
+
+func NewStockService(repository StockRepository) StockService {
+	return &stockService{repo: repository}
+}
+
+func (s *stockService) Adjust(productID uuid.UUID, params StockAdjustmentParams, actor string) (*StockMovement, error) {
+	if params.GetDelta() == 0 {
+		return nil, errors.Wrap(ErrInvalidStockAdjustment, "delta must not be zero")
+	}
+	if !params.GetReason().IsAdjustment() {
+		return nil, errors.Wrapf(ErrInvalidStockAdjustment, "unsupported reason '%s'", params.GetReason())
+	}
+	item := &StockMovement{
+		ProductID:   ProductID(productID),
+		WarehouseID: warehouseID(params.GetWarehouseID()),
+		Delta:       params.GetDelta(),
+		Reason:      params.GetReason(),
+		Actor:       actor,
+		Note:        params.GetNote(),
+		CreatedAt:   time.Now(),
+	}
+	if err := s.repo.Add(item); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return item, nil
+}
+
+func (s *stockService) Movements(productID uuid.UUID, spec *PageSpec) (*StockMovementsPage, error) {
+	return s.repo.FindByProduct(ProductID(productID), spec)
+}
+
+func warehouseID(id *uuid.UUID) *WarehouseID {
+	if id == nil {
+		return nil
+	}
+	warehouseID := WarehouseID(*id)
+	return &warehouseID
+}
@@ -0,0 +1,35 @@
+package application
+
+import (
+	"github.com/jnikolaeva/eshop-common/uuid"
+)
+
+type WarehouseID uuid.UUID
+
+func (u WarehouseID) String() string {
+	return uuid.UUID(u).String()
+}
+
+// Warehouse is a stock location. Quantities set without an explicit warehouse, e.g. available
+// quantity of product writes, belong to the default warehouse.
+type Warehouse struct {
+	ID        WarehouseID
+	Code      string
+	Name      string
+	Position  int
+	IsDefault bool
+}
+
+// WarehouseStock is the available quantity of a product at one warehouse.
+type WarehouseStock struct {
+	WarehouseID WarehouseID
+	Quantity    int
+}
+
+type WarehouseRepository interface {
+	NextID() WarehouseID
+	FindByID(id WarehouseID) (*Warehouse, error)
+	FindAll() ([]*Warehouse, error)
+	Add(item Warehouse) error
+	Update(item Warehouse) error
+}
@@ -0,0 +1,70 @@
+package application
+
+import (
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrWarehouseNotFound  = errors.New("warehouse not found")
+	ErrDuplicateWarehouse = errors.New("warehouse with such code already exists")
+)
+
+type WarehouseParams interface {
+	GetCode() string
+	GetName() string
+	GetPosition() int
+}
+
+type WarehouseService interface {
+	FindByID(id uuid.UUID) (*Warehouse, error)
+	FindAll() ([]*Warehouse, error)
+	Create(params WarehouseParams) (WarehouseID, error)
+	Update(id uuid.UUID, params WarehouseParams) (*Warehouse, error)
+}
+
+type warehouseService struct {
+	repo WarehouseRepository
+}
+
+func NewWarehouseService(repository WarehouseRepository) WarehouseService {
+	return &warehouseService{repo: repository}
+}
+
+func (s *warehouseService) FindByID(id uuid.UUID) (*Warehouse, error) {
+	return s.repo.FindByID(WarehouseID(id))
+}
+
+func (s *warehouseService) FindAll() ([]*Warehouse, error) {
+	return s.repo.FindAll()
+}
+
+func (s *warehouseService) Create(params WarehouseParams) (WarehouseID, error) {
+	item := newWarehouse(s.repo.NextID(), params)
+	if err := s.repo.Add(*item); err != nil {
+		return WarehouseID{}, errors.WithStack(err)
+	}
+	return item.ID, nil
+}
+
+func (s *warehouseService) Update(id uuid.UUID, params WarehouseParams) (*Warehouse, error) {
+	current, err := s.repo.FindByID(WarehouseID(id))
+	if err != nil {
+		return nil, err
+	}
+	item := newWarehouse(current.ID, params)
+	item.IsDefault = current.IsDefault
+	if err = s.repo.Update(*item); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return item, nil
+}
+
+func newWarehouse(id WarehouseID, params WarehouseParams) *Warehouse {
+	return &Warehouse{
+		ID:       id,
+		Code:     params.GetCode(),
+		Name:     params.GetName(),
+		Position: params.GetPosition(),
+	}
+}
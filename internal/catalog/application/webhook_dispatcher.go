@@ -0,0 +1,121 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/go-kit/kit/metrics"
+)
+
+// WebhookSender performs one delivery attempt, any error counts as a failed attempt.
+type WebhookSender interface {
+	Send(ctx context.Context, hook *Webhook, item *WebhookDelivery) error
+}
+
+// WebhookRetryPolicy doubles the delay after every failed attempt up to MaxBackoff. The delivery becomes
+// a dead letter after MaxAttempts failed attempts.
+type WebhookRetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func (p WebhookRetryPolicy) Backoff(attempts int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempts && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
+
+// WebhookDispatcher periodically delivers due webhook deliveries. Deliveries are claimed with SKIP LOCKED,
+// so the dispatcher may run on every replica at the same time.
+type WebhookDispatcher struct {
+	repo     WebhookRepository
+	sender   WebhookSender
+	policy   WebhookRetryPolicy
+	interval time.Duration
+	logger   log.Logger
+	// deliveries counts attempts by "result": delivered, failed or dead
+	deliveries metrics.Counter
+	duration   metrics.Histogram
+}
+
+func NewWebhookDispatcher(
+	repository WebhookRepository,
+	sender WebhookSender,
+	policy WebhookRetryPolicy,
+	interval time.Duration,
+	logger log.Logger,
+	deliveries metrics.Counter,
+	duration metrics.Histogram,
+) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:       repository,
+		sender:     sender,
+		policy:     policy,
+		interval:   interval,
+		logger:     logger,
+		deliveries: deliveries,
+		duration:   duration,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		d.deliverAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *WebhookDispatcher) deliverAll(ctx context.Context) {
+	for ctx.Err() == nil {
+		item, err := d.repo.DeliverNext(func(hook *Webhook, item *WebhookDelivery) {
+			d.deliver(ctx, hook, item)
+		})
+		if err != nil {
+			_ = level.Error(d.logger).Log("msg", "failed to deliver webhook", "err", err)
+			return
+		}
+		if item == nil {
+			return
+		}
+	}
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, hook *Webhook, item *WebhookDelivery) {
+	start := time.Now()
+	err := d.sender.Send(ctx, hook, item)
+	d.duration.Observe(time.Since(start).Seconds())
+
+	item.Attempts++
+	now := time.Now()
+	if err == nil {
+		item.Status = WebhookDeliveryDelivered
+		item.DeliveredAt = &now
+		item.LastError = ""
+		d.deliveries.With("result", "delivered").Add(1)
+		return
+	}
+	item.LastError = err.Error()
+	if item.Attempts >= d.policy.MaxAttempts {
+		item.Status = WebhookDeliveryDead
+		d.deliveries.With("result", "dead").Add(1)
+		_ = level.Warn(d.logger).Log("msg", "webhook delivery moved to dead letters", "webhook", hook.ID, "delivery", item.ID, "err", err)
+		return
+	}
+	item.NextAttemptAt = now.Add(d.policy.Backoff(item.Attempts))
+	d.deliveries.With("result", "failed").Add(1)
+}
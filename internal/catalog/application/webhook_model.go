@@ -0,0 +1,68 @@
+package application
+
+import (
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+)
+
+type WebhookID uuid.UUID
+
+func (u WebhookID) String() string {
+	return uuid.UUID(u).String()
+}
+
+// Webhook is a partner subscription to domain events. Empty ProductIDs subscribes to all products.
+type Webhook struct {
+	ID         WebhookID
+	URL        string
+	EventTypes []EventType
+	ProductIDs []ProductID
+	// Secret signs delivered payloads with HMAC-SHA256
+	Secret    string
+	Active    bool
+	CreatedAt time.Time
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead marks deliveries that ran out of attempts, they are kept as dead letters
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is an event queued for one webhook when the event is stored in the outbox.
+type WebhookDelivery struct {
+	ID            int64
+	WebhookID     WebhookID
+	Event         Event
+	Status        WebhookDeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	DeliveredAt   *time.Time
+}
+
+type WebhookDeliveriesPage struct {
+	Items   []*WebhookDelivery
+	HasNext bool
+	HasPrev bool
+}
+
+type WebhookRepository interface {
+	NextID() WebhookID
+	FindByID(id WebhookID) (*Webhook, error)
+	FindAll() ([]*Webhook, error)
+	Add(item Webhook) error
+	Update(item Webhook) error
+	Delete(id WebhookID) error
+	// FindDeadLetters returns dead deliveries, newest first, webhookID nil means all webhooks
+	FindDeadLetters(webhookID *WebhookID, spec *PageSpec) (*WebhookDeliveriesPage, error)
+	// Redeliver moves the dead delivery back to the queue with a fresh attempts budget
+	Redeliver(id int64) (*WebhookDelivery, error)
+	// DeliverNext locks the next due delivery of an active webhook, skipping deliveries locked by other
+	// workers, and stores the outcome deliver sets on it. It returns nil when no delivery is due.
+	DeliverNext(deliver func(hook *Webhook, item *WebhookDelivery)) (*WebhookDelivery, error)
+}
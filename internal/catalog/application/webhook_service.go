@@ -0,0 +1,148 @@
+package application
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidWebhook     = errors.New("invalid webhook")
+	ErrWebhookNotFound    = errors.New("webhook not found")
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+)
+
+var webhookEventTypes = map[EventType]bool{
+	EventProductCreated: true,
+	EventProductUpdated: true,
+	EventProductDeleted: true,
+	EventStockChanged:   true,
+}
+
+type WebhookParams interface {
+	GetURL() string
+	GetEventTypes() []EventType
+	GetProductIDs() []uuid.UUID
+	// GetSecret returns an empty string to generate a secret on create and to keep the current one on update
+	GetSecret() string
+	GetActive() bool
+}
+
+type WebhookService interface {
+	FindByID(id uuid.UUID) (*Webhook, error)
+	FindAll() ([]*Webhook, error)
+	Create(params WebhookParams) (*Webhook, error)
+	Update(id uuid.UUID, params WebhookParams) (*Webhook, error)
+	Delete(id uuid.UUID) error
+	DeadLetters(webhookID *uuid.UUID, spec *PageSpec) (*WebhookDeliveriesPage, error)
+	Redeliver(id int64) (*WebhookDelivery, error)
+}
+
+type webhookService struct {
+	repo WebhookRepository
+}
+
+func NewWebhookService(repository WebhookRepository) WebhookService {
+	return &webhookService{repo: repository}
+}
+
+func (s *webhookService) FindByID(id uuid.UUID) (*Webhook, error) {
+	return s.repo.FindByID(WebhookID(id))
+}
+
+func (s *webhookService) FindAll() ([]*Webhook, error) {
+	return s.repo.FindAll()
+}
+
+func (s *webhookService) Create(params WebhookParams) (*Webhook, error) {
+	item, err := newWebhook(s.repo.NextID(), params)
+	if err != nil {
+		return nil, err
+	}
+	if item.Secret == "" {
+		if item.Secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.repo.Add(*item); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return item, nil
+}
+
+func (s *webhookService) Update(id uuid.UUID, params WebhookParams) (*Webhook, error) {
+	current, err := s.repo.FindByID(WebhookID(id))
+	if err != nil {
+		return nil, err
+	}
+	item, err := newWebhook(current.ID, params)
+	if err != nil {
+		return nil, err
+	}
+	if item.Secret == "" {
+		item.Secret = current.Secret
+	}
+	item.CreatedAt = current.CreatedAt
+	if err = s.repo.Update(*item); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return item, nil
+}
+
+func (s *webhookService) Delete(id uuid.UUID) error {
+	return s.repo.Delete(WebhookID(id))
+}
+
+func (s *webhookService) DeadLetters(webhookID *uuid.UUID, spec *PageSpec) (*WebhookDeliveriesPage, error) {
+	var id *WebhookID
+	if webhookID != nil {
+		if _, err := s.repo.FindByID(WebhookID(*webhookID)); err != nil {
+			return nil, err
+		}
+		converted := WebhookID(*webhookID)
+		id = &converted
+	}
+	return s.repo.FindDeadLetters(id, spec)
+}
+
+func (s *webhookService) Redeliver(id int64) (*WebhookDelivery, error) {
+	return s.repo.Redeliver(id)
+}
+
+func newWebhook(id WebhookID, params WebhookParams) (*Webhook, error) {
+	u, err := url.Parse(params.GetURL())
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.Wrapf(ErrInvalidWebhook, "url '%s' must be an absolute http or https URL", params.GetURL())
+	}
+	eventTypes := params.GetEventTypes()
+	if len(eventTypes) == 0 {
+		return nil, errors.Wrap(ErrInvalidWebhook, "at least one event type is required")
+	}
+	for _, t := range eventTypes {
+		if !webhookEventTypes[t] {
+			return nil, errors.Wrapf(ErrInvalidWebhook, "unknown event type '%s'", t)
+		}
+	}
+	item := &Webhook{
+		ID:         id,
+		URL:        params.GetURL(),
+		EventTypes: eventTypes,
+		Secret:     params.GetSecret(),
+		Active:     params.GetActive(),
+	}
+	for _, productID := range params.GetProductIDs() {
+		item.ProductIDs = append(item.ProductIDs, ProductID(productID))
+	}
+	return item, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return hex.EncodeToString(b), nil
+}
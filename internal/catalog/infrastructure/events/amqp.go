@@ -0,0 +1,121 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/streadway/amqp"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+// AMQPPublisher publishes events to a durable topic exchange with the event type as the routing key.
+// It waits for broker confirms, so a batch is reported published only when the broker has accepted it.
+// Any AMQP 0-9-1 broker works, e.g. a local RabbitMQ container.
+type AMQPPublisher struct {
+	url      string
+	exchange string
+
+	mu       sync.Mutex
+	conn     *amqp.Connection
+	channel  *amqp.Channel
+	confirms chan amqp.Confirmation
+}
+
+func NewAMQPPublisher(url, exchange string) (*AMQPPublisher, error) {
+	p := &AMQPPublisher{url: url, exchange: exchange}
+	if err := p.connect(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *AMQPPublisher) Publish(ctx context.Context, events []application.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil || p.conn.IsClosed() {
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+	for _, event := range events {
+		body, err := EncodeMessage(event)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		err = p.channel.Publish(p.exchange, string(event.Type), false, false, amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    event.ID.String(),
+			Timestamp:    event.OccurredAt,
+			Type:         string(event.Type),
+			Body:         body,
+		})
+		if err != nil {
+			p.reset()
+			return errors.WithStack(err)
+		}
+	}
+	// all confirmations of the batch are read even after a nack, otherwise they would be taken
+	// for confirmations of the next batch
+	var rejected error
+	for range events {
+		select {
+		case confirm, ok := <-p.confirms:
+			if !ok {
+				p.reset()
+				return errors.New("amqp channel closed before all events were confirmed")
+			}
+			if !confirm.Ack && rejected == nil {
+				rejected = errors.Errorf("amqp broker rejected event delivery %d", confirm.DeliveryTag)
+			}
+		case <-ctx.Done():
+			// unconfirmed deliveries would be mixed up with the next batch
+			p.reset()
+			return ctx.Err()
+		}
+	}
+	return rejected
+}
+
+func (p *AMQPPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn = nil
+	return err
+}
+
+func (p *AMQPPublisher) connect() error {
+	conn, err := amqp.Dial(p.url)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	channel, err := conn.Channel()
+	if err == nil {
+		err = channel.ExchangeDeclare(p.exchange, amqp.ExchangeTopic, true, false, false, false, nil)
+	}
+	if err == nil {
+		err = channel.Confirm(false)
+	}
+	if err != nil {
+		_ = conn.Close()
+		return errors.WithStack(err)
+	}
+	p.conn = conn
+	p.channel = channel
+	p.confirms = channel.NotifyPublish(make(chan amqp.Confirmation, 256))
+	return nil
+}
+
+func (p *AMQPPublisher) reset() {
+	if p.conn != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+	}
+}
@@ -0,0 +1,32 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+// MemoryPublisher keeps published events in process, it is meant for tests.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []application.Event
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(_ context.Context, events []application.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, events...)
+	return nil
+}
+
+// Events returns a copy of all events published so far.
+func (p *MemoryPublisher) Events() []application.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]application.Event(nil), p.events...)
+}
@@ -0,0 +1,28 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+// message is the wire format shared by all publishers.
+type message struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// EncodeMessage returns the JSON document delivered to subscribers for the event.
+func EncodeMessage(event application.Event) ([]byte, error) {
+	return json.Marshal(message{
+		ID:          event.ID.String(),
+		Type:        string(event.Type),
+		AggregateID: event.AggregateID.String(),
+		OccurredAt:  event.OccurredAt.UTC(),
+		Payload:     event.Payload,
+	})
+}
@@ -0,0 +1,123 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/jnikolaeva/eshop-common/uuid"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+// memoryOutbox marks events published only when publish succeeds, like the PostgreSQL outbox.
+type memoryOutbox struct {
+	mu        sync.Mutex
+	events    []application.Event
+	published []bool
+}
+
+func (o *memoryOutbox) PublishPending(limit int, publish func(events []application.Event) error) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var batch []application.Event
+	var indexes []int
+	for i, event := range o.events {
+		if !o.published[i] && len(batch) < limit {
+			batch = append(batch, event)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	if err := publish(batch); err != nil {
+		return 0, err
+	}
+	for _, i := range indexes {
+		o.published[i] = true
+	}
+	return len(batch), nil
+}
+
+func (o *memoryOutbox) pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	count := 0
+	for _, published := range o.published {
+		if !published {
+			count++
+		}
+	}
+	return count
+}
+
+// nackingPublisher rejects the first batch like a broker nacking one of its deliveries
+// and passes the next batches to the memory publisher.
+type nackingPublisher struct {
+	*MemoryPublisher
+	mu      sync.Mutex
+	nacked  bool
+	batches int
+}
+
+func (p *nackingPublisher) Publish(ctx context.Context, events []application.Event) error {
+	p.mu.Lock()
+	p.batches++
+	nack := !p.nacked
+	p.nacked = true
+	p.mu.Unlock()
+	if nack {
+		return errors.New("broker rejected event delivery")
+	}
+	return p.MemoryPublisher.Publish(ctx, events)
+}
+
+func TestOutboxRelayRepublishesNackedBatch(t *testing.T) {
+	outbox := &memoryOutbox{}
+	for i := 0; i < 3; i++ {
+		outbox.events = append(outbox.events, application.Event{
+			ID:          uuid.Generate(),
+			Type:        application.EventProductCreated,
+			AggregateID: application.ProductID(uuid.Generate()),
+			Payload:     []byte(`{}`),
+			OccurredAt:  time.Now(),
+		})
+	}
+	outbox.published = make([]bool, len(outbox.events))
+	publisher := &nackingPublisher{MemoryPublisher: NewMemoryPublisher()}
+	relay := application.NewOutboxRelay(outbox, publisher, time.Millisecond, 10, log.NewNopLogger(), discard.NewCounter())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+	for outbox.pending() > 0 && ctx.Err() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if pending := outbox.pending(); pending != 0 {
+		t.Fatalf("%d events are left unpublished", pending)
+	}
+	if publisher.batches < 2 {
+		t.Fatalf("nacked batch has not been published again, %d batches published", publisher.batches)
+	}
+	published := publisher.Events()
+	if len(published) != len(outbox.events) {
+		t.Fatalf("broker accepted %d events, want %d", len(published), len(outbox.events))
+	}
+	for i, event := range published {
+		if event.ID != outbox.events[i].ID {
+			t.Fatalf("event %d is %s, want %s", i, event.ID, outbox.events[i].ID)
+		}
+	}
+}
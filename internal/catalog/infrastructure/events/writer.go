@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+// WriterPublisher writes events as newline delimited JSON, e.g. to a file consumed by tests or local tools.
+type WriterPublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{w: w}
+}
+
+// NewFilePublisher appends events to the file, "-" stands for stdout.
+func NewFilePublisher(path string) (*WriterPublisher, io.Closer, error) {
+	if path == "-" {
+		return NewWriterPublisher(os.Stdout), nopCloser{}, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return NewWriterPublisher(f), f, nil
+}
+
+func (p *WriterPublisher) Publish(_ context.Context, events []application.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, event := range events {
+		b, err := EncodeMessage(event)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if _, err = p.w.Write(append(b, '\n')); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+	httptransport "github.com/jnikolaeva/catalogservice/internal/catalog/infrastructure/http"
+)
+
+// translateError maps errors to status codes matching the HTTP statuses of the HTTP transport.
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, httptransport.ErrBadRequest),
+		errors.Is(err, application.ErrInvalidVariant),
+		errors.Is(err, application.ErrInvalidSKU),
+		errors.Is(err, application.ErrInvalidParent),
+		errors.Is(err, application.ErrInvalidStockAdjustment),
+		errors.Is(err, application.ErrInvalidWebhook),
+		errors.Is(err, application.ErrInvalidCurrency),
+		errors.Is(err, application.ErrAvailableQtyChange),
+		errors.Is(err, application.ErrAvailableQtyRequired):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, application.ErrProductNotFound),
+		errors.Is(err, application.ErrCategoryNotFound),
+		errors.Is(err, application.ErrReservationNotFound),
+		errors.Is(err, application.ErrWarehouseNotFound),
+		errors.Is(err, application.ErrWebhookNotFound),
+		errors.Is(err, application.ErrDeadLetterNotFound),
+		errors.Is(err, application.ErrImportJobNotFound),
+		errors.Is(err, application.ErrPriceListNotFound),
+		errors.Is(err, application.ErrPriceNotFound),
+		errors.Is(err, application.ErrScheduledPriceNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, application.ErrDuplicateProduct),
+		errors.Is(err, application.ErrDuplicateCategory),
+		errors.Is(err, application.ErrDuplicateWarehouse),
+		errors.Is(err, application.ErrDuplicatePriceList):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, application.ErrVersionMismatch):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, httptransport.ErrPreconditionRequired),
+		errors.Is(err, application.ErrCategoryHasChildren),
+		errors.Is(err, application.ErrInsufficientStock),
+		errors.Is(err, application.ErrReservationClosed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, "unexpected error")
+	}
+}
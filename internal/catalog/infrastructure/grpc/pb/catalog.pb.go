@@ -0,0 +1,1277 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: catalog.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	wrappers "github.com/golang/protobuf/ptypes/wrappers"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Image struct {
+	Url                  string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Width                int32    `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height               int32    `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Image) Reset()         { *m = Image{} }
+func (m *Image) String() string { return proto.CompactTextString(m) }
+func (*Image) ProtoMessage()    {}
+func (*Image) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{0}
+}
+
+func (m *Image) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Image.Unmarshal(m, b)
+}
+func (m *Image) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Image.Marshal(b, m, deterministic)
+}
+func (m *Image) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Image.Merge(m, src)
+}
+func (m *Image) XXX_Size() int {
+	return xxx_messageInfo_Image.Size(m)
+}
+func (m *Image) XXX_DiscardUnknown() {
+	xxx_messageInfo_Image.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Image proto.InternalMessageInfo
+
+func (m *Image) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Image) GetWidth() int32 {
+	if m != nil {
+		return m.Width
+	}
+	return 0
+}
+
+func (m *Image) GetHeight() int32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type WarehouseStock struct {
+	WarehouseId          string   `protobuf:"bytes,1,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	AvailableQty         int32    `protobuf:"varint,2,opt,name=available_qty,json=availableQty,proto3" json:"available_qty,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WarehouseStock) Reset()         { *m = WarehouseStock{} }
+func (m *WarehouseStock) String() string { return proto.CompactTextString(m) }
+func (*WarehouseStock) ProtoMessage()    {}
+func (*WarehouseStock) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{1}
+}
+
+func (m *WarehouseStock) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WarehouseStock.Unmarshal(m, b)
+}
+func (m *WarehouseStock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WarehouseStock.Marshal(b, m, deterministic)
+}
+func (m *WarehouseStock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WarehouseStock.Merge(m, src)
+}
+func (m *WarehouseStock) XXX_Size() int {
+	return xxx_messageInfo_WarehouseStock.Size(m)
+}
+func (m *WarehouseStock) XXX_DiscardUnknown() {
+	xxx_messageInfo_WarehouseStock.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WarehouseStock proto.InternalMessageInfo
+
+func (m *WarehouseStock) GetWarehouseId() string {
+	if m != nil {
+		return m.WarehouseId
+	}
+	return ""
+}
+
+func (m *WarehouseStock) GetAvailableQty() int32 {
+	if m != nil {
+		return m.AvailableQty
+	}
+	return 0
+}
+
+type PriceRange struct {
+	Min                  string   `protobuf:"bytes,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max                  string   `protobuf:"bytes,2,opt,name=max,proto3" json:"max,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PriceRange) Reset()         { *m = PriceRange{} }
+func (m *PriceRange) String() string { return proto.CompactTextString(m) }
+func (*PriceRange) ProtoMessage()    {}
+func (*PriceRange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{2}
+}
+
+func (m *PriceRange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PriceRange.Unmarshal(m, b)
+}
+func (m *PriceRange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PriceRange.Marshal(b, m, deterministic)
+}
+func (m *PriceRange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PriceRange.Merge(m, src)
+}
+func (m *PriceRange) XXX_Size() int {
+	return xxx_messageInfo_PriceRange.Size(m)
+}
+func (m *PriceRange) XXX_DiscardUnknown() {
+	xxx_messageInfo_PriceRange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PriceRange proto.InternalMessageInfo
+
+func (m *PriceRange) GetMin() string {
+	if m != nil {
+		return m.Min
+	}
+	return ""
+}
+
+func (m *PriceRange) GetMax() string {
+	if m != nil {
+		return m.Max
+	}
+	return ""
+}
+
+type Product struct {
+	Id    string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title string `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Sku   string `protobuf:"bytes,3,opt,name=sku,proto3" json:"sku,omitempty"`
+	// price is a decimal number
+	Price        string            `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	AvailableQty int32             `protobuf:"varint,5,opt,name=available_qty,json=availableQty,proto3" json:"available_qty,omitempty"`
+	Stock        []*WarehouseStock `protobuf:"bytes,6,rep,name=stock,proto3" json:"stock,omitempty"`
+	Image        *Image            `protobuf:"bytes,7,opt,name=image,proto3" json:"image,omitempty"`
+	Color        string            `protobuf:"bytes,8,opt,name=color,proto3" json:"color,omitempty"`
+	Material     string            `protobuf:"bytes,9,opt,name=material,proto3" json:"material,omitempty"`
+	ParentId     string            `protobuf:"bytes,10,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	VariantAxes  []string          `protobuf:"bytes,11,rep,name=variant_axes,json=variantAxes,proto3" json:"variant_axes,omitempty"`
+	Options      map[string]string `protobuf:"bytes,12,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Variants     []*Product        `protobuf:"bytes,13,rep,name=variants,proto3" json:"variants,omitempty"`
+	PriceRange   *PriceRange       `protobuf:"bytes,14,opt,name=price_range,json=priceRange,proto3" json:"price_range,omitempty"`
+	Relevance    float64           `protobuf:"fixed64,15,opt,name=relevance,proto3" json:"relevance,omitempty"`
+	Highlight    string            `protobuf:"bytes,16,opt,name=highlight,proto3" json:"highlight,omitempty"`
+	// currency is the ISO 4217 code of the prices
+	Currency string `protobuf:"bytes,17,opt,name=currency,proto3" json:"currency,omitempty"`
+	// compare_at_price is the regular price while a sale price is in effect, empty otherwise
+	CompareAtPrice       string   `protobuf:"bytes,18,opt,name=compare_at_price,json=compareAtPrice,proto3" json:"compare_at_price,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Product) Reset()         { *m = Product{} }
+func (m *Product) String() string { return proto.CompactTextString(m) }
+func (*Product) ProtoMessage()    {}
+func (*Product) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{3}
+}
+
+func (m *Product) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Product.Unmarshal(m, b)
+}
+func (m *Product) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Product.Marshal(b, m, deterministic)
+}
+func (m *Product) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Product.Merge(m, src)
+}
+func (m *Product) XXX_Size() int {
+	return xxx_messageInfo_Product.Size(m)
+}
+func (m *Product) XXX_DiscardUnknown() {
+	xxx_messageInfo_Product.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Product proto.InternalMessageInfo
+
+func (m *Product) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Product) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *Product) GetSku() string {
+	if m != nil {
+		return m.Sku
+	}
+	return ""
+}
+
+func (m *Product) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *Product) GetAvailableQty() int32 {
+	if m != nil {
+		return m.AvailableQty
+	}
+	return 0
+}
+
+func (m *Product) GetStock() []*WarehouseStock {
+	if m != nil {
+		return m.Stock
+	}
+	return nil
+}
+
+func (m *Product) GetImage() *Image {
+	if m != nil {
+		return m.Image
+	}
+	return nil
+}
+
+func (m *Product) GetColor() string {
+	if m != nil {
+		return m.Color
+	}
+	return ""
+}
+
+func (m *Product) GetMaterial() string {
+	if m != nil {
+		return m.Material
+	}
+	return ""
+}
+
+func (m *Product) GetParentId() string {
+	if m != nil {
+		return m.ParentId
+	}
+	return ""
+}
+
+func (m *Product) GetVariantAxes() []string {
+	if m != nil {
+		return m.VariantAxes
+	}
+	return nil
+}
+
+func (m *Product) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *Product) GetVariants() []*Product {
+	if m != nil {
+		return m.Variants
+	}
+	return nil
+}
+
+func (m *Product) GetPriceRange() *PriceRange {
+	if m != nil {
+		return m.PriceRange
+	}
+	return nil
+}
+
+func (m *Product) GetRelevance() float64 {
+	if m != nil {
+		return m.Relevance
+	}
+	return 0
+}
+
+func (m *Product) GetHighlight() string {
+	if m != nil {
+		return m.Highlight
+	}
+	return ""
+}
+
+func (m *Product) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *Product) GetCompareAtPrice() string {
+	if m != nil {
+		return m.CompareAtPrice
+	}
+	return ""
+}
+
+type GetProductRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// currency and price_list select the prices like the query parameters of the HTTP API
+	Currency             string   `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	PriceList            string   `protobuf:"bytes,3,opt,name=price_list,json=priceList,proto3" json:"price_list,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetProductRequest) Reset()         { *m = GetProductRequest{} }
+func (m *GetProductRequest) String() string { return proto.CompactTextString(m) }
+func (*GetProductRequest) ProtoMessage()    {}
+func (*GetProductRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{4}
+}
+
+func (m *GetProductRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetProductRequest.Unmarshal(m, b)
+}
+func (m *GetProductRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetProductRequest.Marshal(b, m, deterministic)
+}
+func (m *GetProductRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProductRequest.Merge(m, src)
+}
+func (m *GetProductRequest) XXX_Size() int {
+	return xxx_messageInfo_GetProductRequest.Size(m)
+}
+func (m *GetProductRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProductRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetProductRequest proto.InternalMessageInfo
+
+func (m *GetProductRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *GetProductRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *GetProductRequest) GetPriceList() string {
+	if m != nil {
+		return m.PriceList
+	}
+	return ""
+}
+
+type ListProductsRequest struct {
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageNum  int32 `protobuf:"varint,2,opt,name=page_num,json=pageNum,proto3" json:"page_num,omitempty"`
+	// after is the keyset cursor of the previous page, it replaces page_num
+	After string `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`
+	// with_total is true when unset
+	WithTotal *wrappers.BoolValue `protobuf:"bytes,4,opt,name=with_total,json=withTotal,proto3" json:"with_total,omitempty"`
+	// sort is a comma separated list of fields, fields prefixed with "-" are sorted in descending order
+	Sort                 string              `protobuf:"bytes,5,opt,name=sort,proto3" json:"sort,omitempty"`
+	Q                    string              `protobuf:"bytes,6,opt,name=q,proto3" json:"q,omitempty"`
+	PriceMin             string              `protobuf:"bytes,7,opt,name=price_min,json=priceMin,proto3" json:"price_min,omitempty"`
+	PriceMax             string              `protobuf:"bytes,8,opt,name=price_max,json=priceMax,proto3" json:"price_max,omitempty"`
+	Colors               []string            `protobuf:"bytes,9,rep,name=colors,proto3" json:"colors,omitempty"`
+	Materials            []string            `protobuf:"bytes,10,rep,name=materials,proto3" json:"materials,omitempty"`
+	CategoryId           string              `protobuf:"bytes,11,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	IncludeSubcategories bool                `protobuf:"varint,12,opt,name=include_subcategories,json=includeSubcategories,proto3" json:"include_subcategories,omitempty"`
+	WarehouseId          string              `protobuf:"bytes,13,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	InStock              *wrappers.BoolValue `protobuf:"bytes,14,opt,name=in_stock,json=inStock,proto3" json:"in_stock,omitempty"`
+	GroupVariants        bool                `protobuf:"varint,15,opt,name=group_variants,json=groupVariants,proto3" json:"group_variants,omitempty"`
+	// filter is an expression of the filter language of the HTTP API
+	Filter               string   `protobuf:"bytes,16,opt,name=filter,proto3" json:"filter,omitempty"`
+	Currency             string   `protobuf:"bytes,17,opt,name=currency,proto3" json:"currency,omitempty"`
+	PriceList            string   `protobuf:"bytes,18,opt,name=price_list,json=priceList,proto3" json:"price_list,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListProductsRequest) Reset()         { *m = ListProductsRequest{} }
+func (m *ListProductsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListProductsRequest) ProtoMessage()    {}
+func (*ListProductsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{5}
+}
+
+func (m *ListProductsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListProductsRequest.Unmarshal(m, b)
+}
+func (m *ListProductsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListProductsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListProductsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListProductsRequest.Merge(m, src)
+}
+func (m *ListProductsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListProductsRequest.Size(m)
+}
+func (m *ListProductsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListProductsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListProductsRequest proto.InternalMessageInfo
+
+func (m *ListProductsRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListProductsRequest) GetPageNum() int32 {
+	if m != nil {
+		return m.PageNum
+	}
+	return 0
+}
+
+func (m *ListProductsRequest) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+func (m *ListProductsRequest) GetWithTotal() *wrappers.BoolValue {
+	if m != nil {
+		return m.WithTotal
+	}
+	return nil
+}
+
+func (m *ListProductsRequest) GetSort() string {
+	if m != nil {
+		return m.Sort
+	}
+	return ""
+}
+
+func (m *ListProductsRequest) GetQ() string {
+	if m != nil {
+		return m.Q
+	}
+	return ""
+}
+
+func (m *ListProductsRequest) GetPriceMin() string {
+	if m != nil {
+		return m.PriceMin
+	}
+	return ""
+}
+
+func (m *ListProductsRequest) GetPriceMax() string {
+	if m != nil {
+		return m.PriceMax
+	}
+	return ""
+}
+
+func (m *ListProductsRequest) GetColors() []string {
+	if m != nil {
+		return m.Colors
+	}
+	return nil
+}
+
+func (m *ListProductsRequest) GetMaterials() []string {
+	if m != nil {
+		return m.Materials
+	}
+	return nil
+}
+
+func (m *ListProductsRequest) GetCategoryId() string {
+	if m != nil {
+		return m.CategoryId
+	}
+	return ""
+}
+
+func (m *ListProductsRequest) GetIncludeSubcategories() bool {
+	if m != nil {
+		return m.IncludeSubcategories
+	}
+	return false
+}
+
+func (m *ListProductsRequest) GetWarehouseId() string {
+	if m != nil {
+		return m.WarehouseId
+	}
+	return ""
+}
+
+func (m *ListProductsRequest) GetInStock() *wrappers.BoolValue {
+	if m != nil {
+		return m.InStock
+	}
+	return nil
+}
+
+func (m *ListProductsRequest) GetGroupVariants() bool {
+	if m != nil {
+		return m.GroupVariants
+	}
+	return false
+}
+
+func (m *ListProductsRequest) GetFilter() string {
+	if m != nil {
+		return m.Filter
+	}
+	return ""
+}
+
+func (m *ListProductsRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *ListProductsRequest) GetPriceList() string {
+	if m != nil {
+		return m.PriceList
+	}
+	return ""
+}
+
+type ListProductsResponse struct {
+	Items []*Product `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	After string     `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	Count int32      `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	// total is zero when with_total is false
+	Total                int32    `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	HasNext              bool     `protobuf:"varint,5,opt,name=has_next,json=hasNext,proto3" json:"has_next,omitempty"`
+	HasPrev              bool     `protobuf:"varint,6,opt,name=has_prev,json=hasPrev,proto3" json:"has_prev,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListProductsResponse) Reset()         { *m = ListProductsResponse{} }
+func (m *ListProductsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListProductsResponse) ProtoMessage()    {}
+func (*ListProductsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{6}
+}
+
+func (m *ListProductsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListProductsResponse.Unmarshal(m, b)
+}
+func (m *ListProductsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListProductsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListProductsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListProductsResponse.Merge(m, src)
+}
+func (m *ListProductsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListProductsResponse.Size(m)
+}
+func (m *ListProductsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListProductsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListProductsResponse proto.InternalMessageInfo
+
+func (m *ListProductsResponse) GetItems() []*Product {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *ListProductsResponse) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+func (m *ListProductsResponse) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ListProductsResponse) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *ListProductsResponse) GetHasNext() bool {
+	if m != nil {
+		return m.HasNext
+	}
+	return false
+}
+
+func (m *ListProductsResponse) GetHasPrev() bool {
+	if m != nil {
+		return m.HasPrev
+	}
+	return false
+}
+
+type CreateProductRequest struct {
+	Title        string                `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Sku          string                `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Price        string                `protobuf:"bytes,3,opt,name=price,proto3" json:"price,omitempty"`
+	AvailableQty int32                 `protobuf:"varint,4,opt,name=available_qty,json=availableQty,proto3" json:"available_qty,omitempty"`
+	Image        *Image                `protobuf:"bytes,5,opt,name=image,proto3" json:"image,omitempty"`
+	Color        string                `protobuf:"bytes,6,opt,name=color,proto3" json:"color,omitempty"`
+	Material     string                `protobuf:"bytes,7,opt,name=material,proto3" json:"material,omitempty"`
+	ParentId     *wrappers.StringValue `protobuf:"bytes,8,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	VariantAxes  []string              `protobuf:"bytes,9,rep,name=variant_axes,json=variantAxes,proto3" json:"variant_axes,omitempty"`
+	Options      map[string]string     `protobuf:"bytes,10,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// currency is USD when empty
+	Currency             string   `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateProductRequest) Reset()         { *m = CreateProductRequest{} }
+func (m *CreateProductRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateProductRequest) ProtoMessage()    {}
+func (*CreateProductRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{7}
+}
+
+func (m *CreateProductRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateProductRequest.Unmarshal(m, b)
+}
+func (m *CreateProductRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateProductRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateProductRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateProductRequest.Merge(m, src)
+}
+func (m *CreateProductRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateProductRequest.Size(m)
+}
+func (m *CreateProductRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateProductRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateProductRequest proto.InternalMessageInfo
+
+func (m *CreateProductRequest) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *CreateProductRequest) GetSku() string {
+	if m != nil {
+		return m.Sku
+	}
+	return ""
+}
+
+func (m *CreateProductRequest) GetPrice() string {
+	if m != nil {
+		return m.Price
+	}
+	return ""
+}
+
+func (m *CreateProductRequest) GetAvailableQty() int32 {
+	if m != nil {
+		return m.AvailableQty
+	}
+	return 0
+}
+
+func (m *CreateProductRequest) GetImage() *Image {
+	if m != nil {
+		return m.Image
+	}
+	return nil
+}
+
+func (m *CreateProductRequest) GetColor() string {
+	if m != nil {
+		return m.Color
+	}
+	return ""
+}
+
+func (m *CreateProductRequest) GetMaterial() string {
+	if m != nil {
+		return m.Material
+	}
+	return ""
+}
+
+func (m *CreateProductRequest) GetParentId() *wrappers.StringValue {
+	if m != nil {
+		return m.ParentId
+	}
+	return nil
+}
+
+func (m *CreateProductRequest) GetVariantAxes() []string {
+	if m != nil {
+		return m.VariantAxes
+	}
+	return nil
+}
+
+func (m *CreateProductRequest) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *CreateProductRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+type CreateProductResponse struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateProductResponse) Reset()         { *m = CreateProductResponse{} }
+func (m *CreateProductResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateProductResponse) ProtoMessage()    {}
+func (*CreateProductResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{8}
+}
+
+func (m *CreateProductResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateProductResponse.Unmarshal(m, b)
+}
+func (m *CreateProductResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateProductResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateProductResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateProductResponse.Merge(m, src)
+}
+func (m *CreateProductResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateProductResponse.Size(m)
+}
+func (m *CreateProductResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateProductResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateProductResponse proto.InternalMessageInfo
+
+func (m *CreateProductResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type BatchGetProductsRequest struct {
+	Ids                  []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	Currency             string   `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	PriceList            string   `protobuf:"bytes,3,opt,name=price_list,json=priceList,proto3" json:"price_list,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchGetProductsRequest) Reset()         { *m = BatchGetProductsRequest{} }
+func (m *BatchGetProductsRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchGetProductsRequest) ProtoMessage()    {}
+func (*BatchGetProductsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{9}
+}
+
+func (m *BatchGetProductsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchGetProductsRequest.Unmarshal(m, b)
+}
+func (m *BatchGetProductsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchGetProductsRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchGetProductsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchGetProductsRequest.Merge(m, src)
+}
+func (m *BatchGetProductsRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchGetProductsRequest.Size(m)
+}
+func (m *BatchGetProductsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchGetProductsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchGetProductsRequest proto.InternalMessageInfo
+
+func (m *BatchGetProductsRequest) GetIds() []string {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+func (m *BatchGetProductsRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *BatchGetProductsRequest) GetPriceList() string {
+	if m != nil {
+		return m.PriceList
+	}
+	return ""
+}
+
+type BatchGetProductsResponse struct {
+	// Types that are valid to be assigned to Result:
+	//	*BatchGetProductsResponse_Product
+	//	*BatchGetProductsResponse_MissingId
+	Result               isBatchGetProductsResponse_Result `protobuf_oneof:"result"`
+	XXX_NoUnkeyedLiteral struct{}                          `json:"-"`
+	XXX_unrecognized     []byte                            `json:"-"`
+	XXX_sizecache        int32                             `json:"-"`
+}
+
+func (m *BatchGetProductsResponse) Reset()         { *m = BatchGetProductsResponse{} }
+func (m *BatchGetProductsResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchGetProductsResponse) ProtoMessage()    {}
+func (*BatchGetProductsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0abbfcf058acdf89, []int{10}
+}
+
+func (m *BatchGetProductsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchGetProductsResponse.Unmarshal(m, b)
+}
+func (m *BatchGetProductsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchGetProductsResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchGetProductsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchGetProductsResponse.Merge(m, src)
+}
+func (m *BatchGetProductsResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchGetProductsResponse.Size(m)
+}
+func (m *BatchGetProductsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchGetProductsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchGetProductsResponse proto.InternalMessageInfo
+
+type isBatchGetProductsResponse_Result interface {
+	isBatchGetProductsResponse_Result()
+}
+
+type BatchGetProductsResponse_Product struct {
+	Product *Product `protobuf:"bytes,1,opt,name=product,proto3,oneof"`
+}
+
+type BatchGetProductsResponse_MissingId struct {
+	MissingId string `protobuf:"bytes,2,opt,name=missing_id,json=missingId,proto3,oneof"`
+}
+
+func (*BatchGetProductsResponse_Product) isBatchGetProductsResponse_Result() {}
+
+func (*BatchGetProductsResponse_MissingId) isBatchGetProductsResponse_Result() {}
+
+func (m *BatchGetProductsResponse) GetResult() isBatchGetProductsResponse_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *BatchGetProductsResponse) GetProduct() *Product {
+	if x, ok := m.GetResult().(*BatchGetProductsResponse_Product); ok {
+		return x.Product
+	}
+	return nil
+}
+
+func (m *BatchGetProductsResponse) GetMissingId() string {
+	if x, ok := m.GetResult().(*BatchGetProductsResponse_MissingId); ok {
+		return x.MissingId
+	}
+	return ""
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*BatchGetProductsResponse) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*BatchGetProductsResponse_Product)(nil),
+		(*BatchGetProductsResponse_MissingId)(nil),
+	}
+}
+
+func init() {
+	proto.RegisterType((*Image)(nil), "catalog.Image")
+	proto.RegisterType((*WarehouseStock)(nil), "catalog.WarehouseStock")
+	proto.RegisterType((*PriceRange)(nil), "catalog.PriceRange")
+	proto.RegisterType((*Product)(nil), "catalog.Product")
+	proto.RegisterMapType((map[string]string)(nil), "catalog.Product.OptionsEntry")
+	proto.RegisterType((*GetProductRequest)(nil), "catalog.GetProductRequest")
+	proto.RegisterType((*ListProductsRequest)(nil), "catalog.ListProductsRequest")
+	proto.RegisterType((*ListProductsResponse)(nil), "catalog.ListProductsResponse")
+	proto.RegisterType((*CreateProductRequest)(nil), "catalog.CreateProductRequest")
+	proto.RegisterMapType((map[string]string)(nil), "catalog.CreateProductRequest.OptionsEntry")
+	proto.RegisterType((*CreateProductResponse)(nil), "catalog.CreateProductResponse")
+	proto.RegisterType((*BatchGetProductsRequest)(nil), "catalog.BatchGetProductsRequest")
+	proto.RegisterType((*BatchGetProductsResponse)(nil), "catalog.BatchGetProductsResponse")
+}
+
+func init() { proto.RegisterFile("catalog.proto", fileDescriptor_0abbfcf058acdf89) }
+
+var fileDescriptor_0abbfcf058acdf89 = []byte{
+	// 1196 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0xdb, 0x72, 0x1b, 0x45,
+	0x13, 0xce, 0x4a, 0xd6, 0x61, 0x5b, 0x87, 0x28, 0x13, 0xe7, 0xcf, 0xfe, 0x22, 0x4e, 0x14, 0x71,
+	0x52, 0x51, 0x41, 0x4a, 0x39, 0x50, 0x90, 0x70, 0x15, 0x07, 0xca, 0x71, 0x01, 0x8e, 0xb3, 0xa6,
+	0xc2, 0xe1, 0x82, 0xad, 0xd1, 0x6a, 0xbc, 0x1a, 0xbc, 0xda, 0x5d, 0xcf, 0xcc, 0xca, 0x52, 0x1e,
+	0x86, 0xc7, 0xe0, 0x8e, 0x47, 0xe0, 0x05, 0x78, 0x1a, 0x6a, 0x0e, 0xbb, 0x92, 0x75, 0x30, 0x54,
+	0xe5, 0x6e, 0xfa, 0xeb, 0x9e, 0xe9, 0x9e, 0xee, 0xaf, 0xbb, 0xa1, 0xe1, 0x63, 0x81, 0xc3, 0x38,
+	0xe8, 0x27, 0x2c, 0x16, 0x31, 0xaa, 0x18, 0xb1, 0x7d, 0x3f, 0x88, 0xe3, 0x20, 0x24, 0x03, 0x05,
+	0x0f, 0xd3, 0xb3, 0xc1, 0x25, 0xc3, 0x49, 0x42, 0x18, 0xd7, 0x86, 0xdd, 0x43, 0x28, 0x1d, 0x4d,
+	0x70, 0x40, 0x50, 0x0b, 0x8a, 0x29, 0x0b, 0x1d, 0xab, 0x63, 0xf5, 0x6c, 0x57, 0x1e, 0xd1, 0x2e,
+	0x94, 0x2e, 0xe9, 0x48, 0x8c, 0x9d, 0x42, 0xc7, 0xea, 0x95, 0x5c, 0x2d, 0xa0, 0xff, 0x41, 0x79,
+	0x4c, 0x68, 0x30, 0x16, 0x4e, 0x51, 0xc1, 0x46, 0xea, 0xfe, 0x04, 0xcd, 0x1f, 0x31, 0x23, 0xe3,
+	0x38, 0xe5, 0xe4, 0x54, 0xc4, 0xfe, 0x39, 0x7a, 0x08, 0xf5, 0xcb, 0x0c, 0xf1, 0xe8, 0xc8, 0x3c,
+	0x5d, 0xcb, 0xb1, 0xa3, 0x11, 0x7a, 0x1f, 0x1a, 0x78, 0x8a, 0x69, 0x88, 0x87, 0x21, 0xf1, 0x2e,
+	0xc4, 0xdc, 0xb8, 0xaa, 0xe7, 0xe0, 0x6b, 0x31, 0xef, 0x3e, 0x06, 0x38, 0x61, 0xd4, 0x27, 0x2e,
+	0x8e, 0x74, 0x9c, 0x13, 0x1a, 0x65, 0x71, 0x4e, 0x68, 0xa4, 0x10, 0x3c, 0x73, 0x0a, 0x06, 0xc1,
+	0xb3, 0xee, 0xef, 0x25, 0xa8, 0x9c, 0xb0, 0x78, 0x94, 0xfa, 0x02, 0x35, 0xa1, 0x90, 0xfb, 0x2e,
+	0xd0, 0x91, 0xfc, 0x95, 0xa0, 0x22, 0x24, 0xc6, 0x5e, 0x0b, 0xf2, 0x0d, 0x7e, 0x9e, 0xaa, 0x2f,
+	0xd9, 0xae, 0x3c, 0x4a, 0xbb, 0x44, 0x7a, 0x75, 0x76, 0xb4, 0x9d, 0x12, 0xd6, 0x03, 0x2e, 0xad,
+	0x07, 0x8c, 0x3e, 0x85, 0x12, 0x97, 0x19, 0x70, 0xca, 0x9d, 0x62, 0xaf, 0xb6, 0x7f, 0xb7, 0x9f,
+	0xd5, 0xe6, 0x6a, 0x82, 0x5c, 0x6d, 0x85, 0x3e, 0x80, 0x12, 0x95, 0x25, 0x70, 0x2a, 0x1d, 0xab,
+	0x57, 0xdb, 0x6f, 0xe6, 0xe6, 0xaa, 0x30, 0xae, 0x56, 0xca, 0x78, 0xfc, 0x38, 0x8c, 0x99, 0x53,
+	0xd5, 0xf1, 0x28, 0x01, 0xb5, 0xa1, 0x3a, 0xc1, 0x82, 0x30, 0x8a, 0x43, 0xc7, 0x56, 0x8a, 0x5c,
+	0x46, 0xef, 0x81, 0x9d, 0x60, 0x46, 0x22, 0x21, 0x93, 0x0f, 0x5a, 0xa9, 0x81, 0xa3, 0x91, 0x2c,
+	0xce, 0x14, 0x33, 0x8a, 0x23, 0xe1, 0xe1, 0x19, 0xe1, 0x4e, 0xad, 0x53, 0x94, 0xc5, 0x31, 0xd8,
+	0xf3, 0x19, 0xe1, 0xe8, 0x0b, 0xa8, 0xc4, 0x89, 0xa0, 0x71, 0xc4, 0x9d, 0xba, 0xfa, 0xc8, 0x5e,
+	0x1e, 0x99, 0x49, 0x6e, 0xff, 0x95, 0xd6, 0x7f, 0x13, 0x09, 0x36, 0x77, 0x33, 0x6b, 0xf4, 0x08,
+	0xaa, 0xe6, 0x1d, 0xee, 0x34, 0xd4, 0xcd, 0xd6, 0xea, 0x4d, 0x37, 0xb7, 0x40, 0x9f, 0x41, 0x4d,
+	0xe5, 0xd6, 0x63, 0xb2, 0xbe, 0x4e, 0x53, 0x25, 0xe1, 0xf6, 0xd2, 0x85, 0xac, 0xf4, 0x2e, 0x24,
+	0xf9, 0x19, 0xdd, 0x03, 0x9b, 0x91, 0x90, 0x4c, 0x71, 0xe4, 0x13, 0xe7, 0x66, 0xc7, 0xea, 0x59,
+	0xee, 0x02, 0x90, 0xda, 0x31, 0x0d, 0xc6, 0xa1, 0xe2, 0x69, 0x4b, 0x7d, 0x7d, 0x01, 0xc8, 0xa4,
+	0xf9, 0x29, 0x63, 0x24, 0xf2, 0xe7, 0xce, 0x2d, 0x9d, 0x97, 0x4c, 0x46, 0x3d, 0x68, 0xf9, 0xf1,
+	0x44, 0xa6, 0xc9, 0xc3, 0xc2, 0xd3, 0x0c, 0x40, 0xca, 0xa6, 0x69, 0xf0, 0xe7, 0x42, 0x85, 0xd4,
+	0x7e, 0x06, 0xf5, 0xe5, 0xef, 0x4b, 0x0a, 0x9d, 0x93, 0x79, 0x46, 0xcc, 0x73, 0x32, 0x97, 0x25,
+	0x9b, 0xe2, 0x30, 0xcd, 0xa9, 0xa6, 0x84, 0x67, 0x85, 0x2f, 0xad, 0xee, 0xaf, 0x70, 0xeb, 0x90,
+	0x88, 0x2c, 0x17, 0xe4, 0x22, 0x25, 0x7c, 0x9d, 0xa9, 0xcb, 0x61, 0x16, 0x56, 0xc2, 0xdc, 0x03,
+	0x9d, 0x0c, 0x2f, 0xa4, 0x5c, 0x18, 0xda, 0xda, 0x0a, 0xf9, 0x8e, 0x72, 0xd1, 0xfd, 0x7b, 0x07,
+	0x6e, 0xcb, 0x83, 0xf1, 0xc0, 0x33, 0x17, 0x8a, 0x12, 0x01, 0xf1, 0x38, 0x7d, 0x4b, 0x94, 0xa7,
+	0x92, 0xa4, 0x44, 0x40, 0x4e, 0xe9, 0x5b, 0x82, 0xfe, 0x0f, 0xea, 0xec, 0x45, 0xe9, 0xc4, 0xf4,
+	0x61, 0x45, 0xca, 0xc7, 0xe9, 0x44, 0xfe, 0x04, 0x9f, 0x09, 0xc2, 0x8c, 0x27, 0x2d, 0xa0, 0xa7,
+	0x00, 0x97, 0x54, 0x8c, 0x3d, 0x11, 0x0b, 0x1c, 0xaa, 0x3e, 0xa9, 0xed, 0xb7, 0xfb, 0x7a, 0xe0,
+	0xf4, 0xb3, 0x81, 0xd3, 0x3f, 0x88, 0xe3, 0xf0, 0x8d, 0xfc, 0xb9, 0x6b, 0x4b, 0xeb, 0x1f, 0xa4,
+	0x31, 0x42, 0xb0, 0xc3, 0x63, 0x26, 0x54, 0xfb, 0xd8, 0xae, 0x3a, 0xa3, 0x3a, 0x58, 0x17, 0x4e,
+	0x59, 0x01, 0xd6, 0x85, 0x0a, 0x55, 0xfd, 0x50, 0x76, 0x7b, 0xc5, 0xb0, 0x57, 0x02, 0xdf, 0xd3,
+	0x68, 0x49, 0x89, 0x67, 0x4e, 0x75, 0x59, 0x89, 0x67, 0x72, 0x42, 0xa9, 0xe6, 0xe0, 0x8e, 0xad,
+	0x48, 0x6d, 0x24, 0x49, 0x8a, 0xac, 0x37, 0xb8, 0x03, 0x4a, 0xb5, 0x00, 0xd0, 0x03, 0xa8, 0xf9,
+	0x58, 0x90, 0x20, 0x66, 0x73, 0xd9, 0x2f, 0x35, 0xf5, 0x28, 0x64, 0xd0, 0xd1, 0x08, 0x3d, 0x81,
+	0x3b, 0x34, 0xf2, 0xc3, 0x74, 0x44, 0x3c, 0x9e, 0x0e, 0x8d, 0x82, 0x12, 0xd9, 0x1c, 0x56, 0xaf,
+	0xea, 0xee, 0x1a, 0xe5, 0xe9, 0xb2, 0x6e, 0x6d, 0x06, 0x36, 0xd6, 0x67, 0xe0, 0xe7, 0x50, 0xa5,
+	0x91, 0xa7, 0x07, 0x46, 0xf3, 0x5f, 0x73, 0x58, 0xa1, 0x91, 0x9e, 0xae, 0x1f, 0x42, 0x33, 0x60,
+	0x71, 0x9a, 0x78, 0x79, 0xab, 0xdd, 0x54, 0x71, 0x34, 0x14, 0xfa, 0xc6, 0x80, 0x32, 0x19, 0x67,
+	0x34, 0x94, 0xa5, 0xd3, 0x6d, 0x60, 0xa4, 0x6b, 0x7b, 0xe0, 0x2a, 0xb9, 0xd0, 0x2a, 0xb9, 0xfe,
+	0xb0, 0x60, 0xf7, 0x2a, 0xb9, 0x78, 0x12, 0x47, 0x9c, 0xa0, 0x8f, 0xa0, 0x44, 0x05, 0x99, 0x70,
+	0xc7, 0xda, 0xd2, 0xf4, 0x5a, 0xbd, 0x60, 0x53, 0x61, 0x99, 0x4d, 0x6a, 0xc0, 0xa5, 0x51, 0xb6,
+	0x57, 0xb4, 0x20, 0xd1, 0x05, 0xbd, 0x4a, 0xae, 0x16, 0x24, 0x55, 0xc7, 0x98, 0x7b, 0x11, 0x99,
+	0x69, 0x0a, 0x55, 0xdd, 0xca, 0x18, 0xf3, 0x63, 0x32, 0x13, 0x99, 0x2a, 0x61, 0x64, 0xea, 0x94,
+	0x73, 0xd5, 0x09, 0x23, 0xd3, 0xee, 0x5f, 0x45, 0xd8, 0x7d, 0xc1, 0x08, 0x16, 0x64, 0xa5, 0xf3,
+	0xf2, 0x9d, 0x60, 0x6d, 0xd8, 0x09, 0x85, 0x0d, 0x3b, 0xa1, 0x78, 0xed, 0x4e, 0xd8, 0xd9, 0xb0,
+	0x13, 0xf2, 0x21, 0x5f, 0xfa, 0x4f, 0x43, 0xbe, 0xbc, 0x6d, 0xc8, 0x57, 0x56, 0x86, 0xfc, 0xd3,
+	0xe5, 0x21, 0x5f, 0x55, 0x6f, 0xdf, 0x5b, 0xa3, 0xcf, 0xa9, 0x60, 0x34, 0x0a, 0x34, 0x81, 0xb6,
+	0xaf, 0x00, 0x7b, 0x7d, 0x05, 0x7c, 0xbd, 0x58, 0x01, 0xa0, 0x6a, 0xfa, 0x49, 0x1e, 0xf7, 0xa6,
+	0x44, 0x6e, 0xd9, 0x07, 0xcb, 0x5c, 0xab, 0x5d, 0xe5, 0xda, 0x3b, 0x4d, 0xd1, 0x8f, 0xe1, 0xce,
+	0x4a, 0x14, 0x86, 0x88, 0x2b, 0x93, 0xb4, 0x7b, 0x06, 0x77, 0x0f, 0xb0, 0xf0, 0xc7, 0x87, 0x64,
+	0x6d, 0x22, 0xb6, 0xa0, 0x48, 0x47, 0x9a, 0xb1, 0xb6, 0x2b, 0x8f, 0xef, 0x32, 0x76, 0x39, 0x38,
+	0xeb, 0x7e, 0x4c, 0x4c, 0x8f, 0xa0, 0x92, 0x68, 0x4c, 0x05, 0xb6, 0xa1, 0x3d, 0x5e, 0xde, 0x70,
+	0x33, 0x13, 0xf4, 0x00, 0x60, 0x42, 0x39, 0xa7, 0x51, 0x20, 0xeb, 0xaa, 0xc2, 0x78, 0x79, 0xc3,
+	0xb5, 0x0d, 0x76, 0x34, 0x3a, 0xa8, 0x42, 0x99, 0x11, 0x9e, 0x86, 0x62, 0xff, 0xcf, 0x02, 0x54,
+	0x5e, 0xe8, 0x97, 0xd0, 0x33, 0x80, 0x85, 0x6f, 0xd4, 0xce, 0x3d, 0xac, 0x2d, 0x9b, 0xf6, 0x9a,
+	0x77, 0xf4, 0x2d, 0xd4, 0x97, 0xbb, 0x1a, 0xdd, 0xcb, 0x2d, 0x36, 0x6c, 0x92, 0xf6, 0xde, 0x16,
+	0xad, 0xf9, 0xed, 0x31, 0x34, 0xae, 0x94, 0x06, 0xed, 0x5d, 0x4b, 0x9c, 0xf6, 0xfd, 0x6d, 0x6a,
+	0xf3, 0xde, 0xcf, 0xd0, 0x5a, 0xcd, 0x2c, 0xea, 0xe4, 0x77, 0xb6, 0x14, 0xb7, 0xfd, 0xf0, 0x1a,
+	0x0b, 0xfd, 0xf0, 0x63, 0xeb, 0xe0, 0xf5, 0x2f, 0xaf, 0x02, 0x2a, 0xc6, 0xe9, 0xb0, 0xef, 0xc7,
+	0x93, 0xc1, 0x6f, 0x11, 0x3d, 0x8f, 0x43, 0x4c, 0xa6, 0x78, 0x60, 0xee, 0x72, 0xc2, 0xa6, 0xd4,
+	0x27, 0x03, 0x1a, 0x09, 0xc2, 0x22, 0x1c, 0x66, 0xf8, 0x80, 0x46, 0x67, 0x0c, 0x73, 0xc1, 0x52,
+	0x5f, 0xa4, 0x8c, 0x0c, 0x02, 0x96, 0xf8, 0x83, 0x64, 0xf8, 0x55, 0x32, 0x1c, 0x96, 0x55, 0xe7,
+	0x3d, 0xf9, 0x67, 0x00, 0x92, 0xbd, 0xf3, 0xe8, 0x95, 0x0b, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// CatalogClient is the client API for Catalog service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CatalogClient interface {
+	GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*Product, error)
+	ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error)
+	CreateProduct(ctx context.Context, in *CreateProductRequest, opts ...grpc.CallOption) (*CreateProductResponse, error)
+	// BatchGetProducts streams one response per requested id in the order of the request.
+	BatchGetProducts(ctx context.Context, in *BatchGetProductsRequest, opts ...grpc.CallOption) (Catalog_BatchGetProductsClient, error)
+}
+
+type catalogClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCatalogClient(cc *grpc.ClientConn) CatalogClient {
+	return &catalogClient{cc}
+}
+
+func (c *catalogClient) GetProduct(ctx context.Context, in *GetProductRequest, opts ...grpc.CallOption) (*Product, error) {
+	out := new(Product)
+	err := c.cc.Invoke(ctx, "/catalog.Catalog/GetProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error) {
+	out := new(ListProductsResponse)
+	err := c.cc.Invoke(ctx, "/catalog.Catalog/ListProducts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) CreateProduct(ctx context.Context, in *CreateProductRequest, opts ...grpc.CallOption) (*CreateProductResponse, error) {
+	out := new(CreateProductResponse)
+	err := c.cc.Invoke(ctx, "/catalog.Catalog/CreateProduct", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) BatchGetProducts(ctx context.Context, in *BatchGetProductsRequest, opts ...grpc.CallOption) (Catalog_BatchGetProductsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Catalog_serviceDesc.Streams[0], "/catalog.Catalog/BatchGetProducts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &catalogBatchGetProductsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Catalog_BatchGetProductsClient interface {
+	Recv() (*BatchGetProductsResponse, error)
+	grpc.ClientStream
+}
+
+type catalogBatchGetProductsClient struct {
+	grpc.ClientStream
+}
+
+func (x *catalogBatchGetProductsClient) Recv() (*BatchGetProductsResponse, error) {
+	m := new(BatchGetProductsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CatalogServer is the server API for Catalog service.
+type CatalogServer interface {
+	GetProduct(context.Context, *GetProductRequest) (*Product, error)
+	ListProducts(context.Context, *ListProductsRequest) (*ListProductsResponse, error)
+	CreateProduct(context.Context, *CreateProductRequest) (*CreateProductResponse, error)
+	// BatchGetProducts streams one response per requested id in the order of the request.
+	BatchGetProducts(*BatchGetProductsRequest, Catalog_BatchGetProductsServer) error
+}
+
+// UnimplementedCatalogServer can be embedded to have forward compatible implementations.
+type UnimplementedCatalogServer struct {
+}
+
+func (*UnimplementedCatalogServer) GetProduct(ctx context.Context, req *GetProductRequest) (*Product, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProduct not implemented")
+}
+func (*UnimplementedCatalogServer) ListProducts(ctx context.Context, req *ListProductsRequest) (*ListProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProducts not implemented")
+}
+func (*UnimplementedCatalogServer) CreateProduct(ctx context.Context, req *CreateProductRequest) (*CreateProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProduct not implemented")
+}
+func (*UnimplementedCatalogServer) BatchGetProducts(req *BatchGetProductsRequest, srv Catalog_BatchGetProductsServer) error {
+	return status.Errorf(codes.Unimplemented, "method BatchGetProducts not implemented")
+}
+
+func RegisterCatalogServer(s *grpc.Server, srv CatalogServer) {
+	s.RegisterService(&_Catalog_serviceDesc, srv)
+}
+
+func _Catalog_GetProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).GetProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/catalog.Catalog/GetProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).GetProduct(ctx, req.(*GetProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).ListProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/catalog.Catalog/ListProducts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).ListProducts(ctx, req.(*ListProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_CreateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).CreateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/catalog.Catalog/CreateProduct",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).CreateProduct(ctx, req.(*CreateProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_BatchGetProducts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchGetProductsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatalogServer).BatchGetProducts(m, &catalogBatchGetProductsServer{stream})
+}
+
+type Catalog_BatchGetProductsServer interface {
+	Send(*BatchGetProductsResponse) error
+	grpc.ServerStream
+}
+
+type catalogBatchGetProductsServer struct {
+	grpc.ServerStream
+}
+
+func (x *catalogBatchGetProductsServer) Send(m *BatchGetProductsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Catalog_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "catalog.Catalog",
+	HandlerType: (*CatalogServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetProduct",
+			Handler:    _Catalog_GetProduct_Handler,
+		},
+		{
+			MethodName: "ListProducts",
+			Handler:    _Catalog_ListProducts_Handler,
+		},
+		{
+			MethodName: "CreateProduct",
+			Handler:    _Catalog_CreateProduct_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BatchGetProducts",
+			Handler:       _Catalog_BatchGetProducts_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "catalog.proto",
+}
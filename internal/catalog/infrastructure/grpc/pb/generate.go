@@ -0,0 +1,5 @@
+// Package pb holds the protobuf definition of the catalog gRPC API, the code is generated with
+// protoc-gen-go v1.3.2.
+package pb
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. catalog.proto
@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-kit/kit/log"
+	gokittransport "github.com/go-kit/kit/transport"
+	gokitgrpc "github.com/go-kit/kit/transport/grpc"
+	"github.com/jnikolaeva/catalogservice/internal/catalog/infrastructure/grpc/pb"
+	httptransport "github.com/jnikolaeva/catalogservice/internal/catalog/infrastructure/http"
+)
+
+type server struct {
+	getProduct    gokitgrpc.Handler
+	listProducts  gokitgrpc.Handler
+	createProduct gokitgrpc.Handler
+	// batchGetProducts responds with products by id, the stream is built by BatchGetProducts
+	batchGetProducts gokitgrpc.Handler
+}
+
+// NewServer serves the catalog over gRPC with the endpoints of the HTTP transport.
+func NewServer(endpoints httptransport.Endpoints, errorLogger log.Logger) pb.CatalogServer {
+	options := []gokitgrpc.ServerOption{
+		gokitgrpc.ServerErrorHandler(gokittransport.NewLogErrorHandler(errorLogger)),
+	}
+	return &server{
+		getProduct:       gokitgrpc.NewServer(endpoints.GetProductByID, decodeGetProductRequest, encodeProductResponse, options...),
+		listProducts:     gokitgrpc.NewServer(endpoints.ListProducts, decodeListProductsRequest, encodeListProductsResponse, options...),
+		createProduct:    gokitgrpc.NewServer(endpoints.CreateProduct, decodeCreateProductRequest, encodeCreateProductResponse, options...),
+		batchGetProducts: gokitgrpc.NewServer(endpoints.BatchGetProducts, decodeBatchGetProductsRequest, encodeBatchGetProductsResponse, options...),
+	}
+}
+
+func (s *server) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.Product, error) {
+	_, res, err := s.getProduct.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return res.(*pb.Product), nil
+}
+
+func (s *server) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
+	_, res, err := s.listProducts.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return res.(*pb.ListProductsResponse), nil
+}
+
+func (s *server) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.CreateProductResponse, error) {
+	_, res, err := s.createProduct.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return res.(*pb.CreateProductResponse), nil
+}
+
+func (s *server) BatchGetProducts(req *pb.BatchGetProductsRequest, stream pb.Catalog_BatchGetProductsServer) error {
+	_, res, err := s.batchGetProducts.ServeGRPC(stream.Context(), req)
+	if err != nil {
+		return translateError(err)
+	}
+	products := res.(map[string]*pb.Product)
+	for _, id := range req.Ids {
+		item := &pb.BatchGetProductsResponse{}
+		if product, ok := products[strings.ToLower(id)]; ok {
+			item.Result = &pb.BatchGetProductsResponse_Product{Product: product}
+		} else {
+			item.Result = &pb.BatchGetProductsResponse_MissingId{MissingId: id}
+		}
+		if err = stream.Send(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
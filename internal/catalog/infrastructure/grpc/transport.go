@@ -0,0 +1,182 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/infrastructure/grpc/pb"
+	httptransport "github.com/jnikolaeva/catalogservice/internal/catalog/infrastructure/http"
+)
+
+const (
+	// actorKey identifies the user or system on whose behalf the catalog is changed, like the X-Actor HTTP header
+	actorKey        = "x-actor"
+	valuesSeparator = ","
+)
+
+var (
+	requestMarshaler    = jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+	responseUnmarshaler = jsonpb.Unmarshaler{AllowUnknownFields: true}
+)
+
+func decodeGetProductRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.GetProductRequest)
+	id, err := uuid.FromString(req.Id)
+	if err != nil {
+		return nil, errors.Wrap(httptransport.ErrBadRequest, "invalid parameter 'id'")
+	}
+	return httptransport.NewGetProductByIDRequest(id, priceSelection(req.Currency, req.PriceList))
+}
+
+// decodeListProductsRequest maps the message to the query parameters of the HTTP listing.
+func decodeListProductsRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.ListProductsRequest)
+	query := url.Values{}
+	setInt(query, "page_size", req.PageSize)
+	setInt(query, "page_num", req.PageNum)
+	setString(query, "after", req.After)
+	if req.WithTotal != nil {
+		query.Set("with_total", strconv.FormatBool(req.WithTotal.Value))
+	}
+	setString(query, "sort", req.Sort)
+	setString(query, "q", req.Q)
+	if req.PriceMin != "" || req.PriceMax != "" {
+		price := req.PriceMin
+		if price == "" {
+			price = "0"
+		}
+		if req.PriceMax != "" {
+			price += valuesSeparator + req.PriceMax
+		}
+		query.Set("price", price)
+	}
+	setString(query, "color", strings.Join(req.Colors, valuesSeparator))
+	setString(query, "material", strings.Join(req.Materials, valuesSeparator))
+	setString(query, "category", req.CategoryId)
+	if req.IncludeSubcategories {
+		query.Set("include_subcategories", "true")
+	}
+	setString(query, "warehouse", req.WarehouseId)
+	if req.InStock != nil {
+		query.Set("in_stock", strconv.FormatBool(req.InStock.Value))
+	}
+	if req.GroupVariants {
+		query.Set("group_variants", "true")
+	}
+	setString(query, "filter", req.Filter)
+	setString(query, "currency", req.Currency)
+	setString(query, "price_list", req.PriceList)
+	return httptransport.NewListProductsRequest(query)
+}
+
+// decodeCreateProductRequest passes the message as the JSON document of the HTTP API, so both transports
+// validate products the same way.
+func decodeCreateProductRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.CreateProductRequest)
+	var body bytes.Buffer
+	if err := requestMarshaler.Marshal(&body, req); err != nil {
+		return nil, errors.Wrap(httptransport.ErrBadRequest, err.Error())
+	}
+	return httptransport.NewCreateProductRequest(&body, actor(ctx))
+}
+
+func decodeBatchGetProductsRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(*pb.BatchGetProductsRequest)
+	ids := make([]uuid.UUID, len(req.Ids))
+	for i, s := range req.Ids {
+		id, err := uuid.FromString(s)
+		if err != nil {
+			return nil, errors.Wrapf(httptransport.ErrBadRequest, "invalid product id '%s'", s)
+		}
+		ids[i] = id
+	}
+	return httptransport.NewBatchGetProductsRequest(ids, nil, priceSelection(req.Currency, req.PriceList))
+}
+
+func encodeProductResponse(_ context.Context, response interface{}) (interface{}, error) {
+	res := &pb.Product{}
+	return res, convertResponse(response, res)
+}
+
+func encodeListProductsResponse(_ context.Context, response interface{}) (interface{}, error) {
+	res := &pb.ListProductsResponse{}
+	return res, convertResponse(response, res)
+}
+
+func encodeCreateProductResponse(_ context.Context, response interface{}) (interface{}, error) {
+	res := &pb.CreateProductResponse{}
+	return res, convertResponse(response, res)
+}
+
+// encodeBatchGetProductsResponse indexes found products by id.
+func encodeBatchGetProductsResponse(_ context.Context, response interface{}) (interface{}, error) {
+	b, err := json.Marshal(response)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var batch struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if err = json.Unmarshal(b, &batch); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	products := make(map[string]*pb.Product, len(batch.Items))
+	for _, raw := range batch.Items {
+		item := &pb.Product{}
+		if err = responseUnmarshaler.Unmarshal(bytes.NewReader(raw), item); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		products[item.Id] = item
+	}
+	return products, nil
+}
+
+// convertResponse converts the HTTP endpoint response to the message through its JSON document.
+func convertResponse(response interface{}, message proto.Message) error {
+	b, err := json.Marshal(response)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(responseUnmarshaler.Unmarshal(bytes.NewReader(b), message))
+}
+
+func actor(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(actorKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// priceSelection maps the price selection fields of a message to the query parameters of the HTTP API.
+func priceSelection(currency, priceList string) url.Values {
+	query := url.Values{}
+	setString(query, "currency", currency)
+	setString(query, "price_list", priceList)
+	return query
+}
+
+func setString(query url.Values, name, value string) {
+	if value != "" {
+		query.Set(name, value)
+	}
+}
+
+func setInt(query url.Values, name string, value int32) {
+	if value != 0 {
+		query.Set(name, strconv.Itoa(int(value)))
+	}
+}
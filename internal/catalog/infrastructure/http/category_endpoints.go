@@ -0,0 +1,121 @@
+package http
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/jnikolaeva/eshop-common/uuid"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+func makeGetCategoryTreeEndpoint(s application.CategoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		nodes, err := s.Tree()
+		if err != nil {
+			return nil, err
+		}
+		return &categoryTreeResponse{Items: toCategoryNodes(nodes)}, nil
+	}
+}
+
+func makeGetCategoryByIDEndpoint(s application.CategoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(*uuid.UUID)
+		item, err := s.FindByID(*id)
+		if err != nil {
+			return nil, err
+		}
+		return &getCategoryByIDResponse{*toCategory(item)}, nil
+	}
+}
+
+func makeCreateCategoryEndpoint(s application.CategoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*createCategoryRequest)
+		id, err := s.Create(req)
+		if err != nil {
+			return nil, err
+		}
+		return &createCategoryResponse{ID: id.String()}, nil
+	}
+}
+
+func makeUpdateCategoryEndpoint(s application.CategoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*updateCategoryRequest)
+		item, err := s.Update(req.ID, req)
+		if err != nil {
+			return nil, err
+		}
+		return &getCategoryByIDResponse{*toCategory(item)}, nil
+	}
+}
+
+func makeDeleteCategoryEndpoint(s application.CategoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(*uuid.UUID)
+		if err := s.Delete(*id); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+}
+
+func makeGetProductCategoriesEndpoint(s application.CategoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(*uuid.UUID)
+		items, err := s.FindByProduct(*id)
+		if err != nil {
+			return nil, err
+		}
+		return &productCategoriesResponse{Items: toCategories(items)}, nil
+	}
+}
+
+func makeSetProductCategoriesEndpoint(s application.CategoryService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*setProductCategoriesRequest)
+		if err := s.SetProductCategories(req.ProductID, req.CategoryIDs); err != nil {
+			return nil, err
+		}
+		items, err := s.FindByProduct(req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		return &productCategoriesResponse{Items: toCategories(items)}, nil
+	}
+}
+
+func toCategory(item *application.Category) *category {
+	result := &category{
+		ID:       item.ID.String(),
+		Slug:     item.Slug,
+		Name:     item.Name,
+		Position: item.Position,
+	}
+	if item.ParentID != nil {
+		parentID := item.ParentID.String()
+		result.ParentID = &parentID
+	}
+	return result
+}
+
+func toCategories(items []*application.Category) []*category {
+	result := make([]*category, len(items))
+	for i, item := range items {
+		result[i] = toCategory(item)
+	}
+	return result
+}
+
+func toCategoryNodes(nodes []*application.CategoryNode) []*categoryNode {
+	result := make([]*categoryNode, len(nodes))
+	for i, node := range nodes {
+		result[i] = &categoryNode{
+			category: *toCategory(&node.Category),
+			Children: toCategoryNodes(node.Children),
+		}
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+)
+
+func decodeCategoryIDRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func decodeCreateCategoryRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req createCategoryRequest
+	if err := decodeCategoryParams(r, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func decodeUpdateCategoryRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	req := updateCategoryRequest{ID: id}
+	if err := decodeCategoryParams(r, &req.createCategoryRequest); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func decodeSetProductCategoriesRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	req := setProductCategoriesRequest{ProductID: id}
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil && e != io.EOF {
+		return nil, errors.Wrap(ErrBadRequest, e.Error())
+	}
+	if req.CategoryIDStrs == nil {
+		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'category_ids'")
+	}
+	req.CategoryIDs = make([]uuid.UUID, len(req.CategoryIDStrs))
+	for i, s := range req.CategoryIDStrs {
+		if req.CategoryIDs[i], err = uuid.FromString(s); err != nil {
+			return nil, errors.Wrapf(ErrBadRequest, "invalid category id '%s'", s)
+		}
+	}
+	return &req, nil
+}
+
+func decodeCategoryParams(r *http.Request, req *createCategoryRequest) error {
+	if e := json.NewDecoder(r.Body).Decode(req); e != nil && e != io.EOF {
+		return errors.Wrap(ErrBadRequest, e.Error())
+	}
+	if req.Slug == "" {
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'slug'")
+	}
+	if req.Name == "" {
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'name'")
+	}
+	if req.ParentIDStr != nil {
+		parentID, err := uuid.FromString(*req.ParentIDStr)
+		if err != nil {
+			return errors.Wrap(ErrBadRequest, "invalid parameter 'parent_id'")
+		}
+		req.ParentID = &parentID
+	}
+	return nil
+}
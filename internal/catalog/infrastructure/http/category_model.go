@@ -0,0 +1,69 @@
+package http
+
+import (
+	"github.com/jnikolaeva/eshop-common/uuid"
+)
+
+type category struct {
+	ID       string  `json:"id"`
+	ParentID *string `json:"parent_id"`
+	Slug     string  `json:"slug"`
+	Name     string  `json:"name"`
+	Position int     `json:"position"`
+}
+
+type categoryNode struct {
+	category
+	Children []*categoryNode `json:"children,omitempty"`
+}
+
+type categoryTreeResponse struct {
+	Items []*categoryNode `json:"items"`
+}
+
+type createCategoryRequest struct {
+	ParentIDStr *string `json:"parent_id"`
+	ParentID    *uuid.UUID
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Position    int    `json:"position"`
+}
+
+func (c *createCategoryRequest) GetParentID() *uuid.UUID {
+	return c.ParentID
+}
+
+func (c *createCategoryRequest) GetSlug() string {
+	return c.Slug
+}
+
+func (c *createCategoryRequest) GetName() string {
+	return c.Name
+}
+
+func (c *createCategoryRequest) GetPosition() int {
+	return c.Position
+}
+
+type createCategoryResponse struct {
+	ID string `json:"id"`
+}
+
+type updateCategoryRequest struct {
+	ID uuid.UUID
+	createCategoryRequest
+}
+
+type getCategoryByIDResponse struct {
+	category
+}
+
+type setProductCategoriesRequest struct {
+	ProductID      uuid.UUID
+	CategoryIDStrs []string `json:"category_ids"`
+	CategoryIDs    []uuid.UUID
+}
+
+type productCategoriesResponse struct {
+	Items []*category `json:"items"`
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+const cursorSignatureSeparator = "."
+
+var errInvalidCursor = errors.Wrap(ErrBadRequest, "invalid cursor")
+
+type cursorPayload struct {
+	Sort   string   `json:"s,omitempty"`
+	Values []string `json:"v,omitempty"`
+	ID     string   `json:"id"`
+}
+
+// cursorCodec makes pagination cursors opaque to clients and protects them from tampering.
+type cursorCodec struct {
+	secret []byte
+}
+
+// encode binds the cursor to the sort order it was issued for.
+func (c cursorCodec) encode(cursor *application.Cursor, sort application.SortSpec) (string, error) {
+	if cursor == nil {
+		return "", nil
+	}
+	payload, err := json.Marshal(cursorPayload{Sort: sort.String(), Values: cursor.Values, ID: cursor.ID.String()})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	encoding := base64.RawURLEncoding
+	return encoding.EncodeToString(payload) + cursorSignatureSeparator + encoding.EncodeToString(c.sign(payload)), nil
+}
+
+func (c cursorCodec) decode(value string, sort application.SortSpec) (*application.Cursor, error) {
+	parts := strings.Split(value, cursorSignatureSeparator)
+	if len(parts) != 2 {
+		return nil, errInvalidCursor
+	}
+	encoding := base64.RawURLEncoding
+	payload, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	signature, err := encoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, c.sign(payload)) {
+		return nil, errInvalidCursor
+	}
+	var p cursorPayload
+	if err = json.Unmarshal(payload, &p); err != nil {
+		return nil, errInvalidCursor
+	}
+	if p.Sort != sort.String() || len(p.Values) != len(sort) {
+		return nil, errors.Wrap(ErrBadRequest, "cursor was issued for another sort order")
+	}
+	id, err := uuid.FromString(p.ID)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	return &application.Cursor{Values: p.Values, ID: application.ProductID(id)}, nil
+}
+
+func (c cursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	_, _ = mac.Write(payload)
+	return mac.Sum(nil)
+}
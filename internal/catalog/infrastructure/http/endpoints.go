@@ -10,34 +10,156 @@ import (
 )
 
 type Endpoints struct {
-	ListProducts   endpoint.Endpoint
-	GetProductByID endpoint.Endpoint
-	CreateProduct  endpoint.Endpoint
+	ListProducts     endpoint.Endpoint
+	GetProductByID   endpoint.Endpoint
+	GetProductBySKU  endpoint.Endpoint
+	BatchGetProducts endpoint.Endpoint
+	GetFacets        endpoint.Endpoint
+	CreateProduct    endpoint.Endpoint
+	UpdateProduct    endpoint.Endpoint
+	PatchProduct     endpoint.Endpoint
+	DeleteProduct    endpoint.Endpoint
+	ImportProducts   endpoint.Endpoint
+	ExportProducts   endpoint.Endpoint
+
+	GetProductCategories endpoint.Endpoint
+	SetProductCategories endpoint.Endpoint
+	GetCategoryTree      endpoint.Endpoint
+	GetCategoryByID      endpoint.Endpoint
+	CreateCategory       endpoint.Endpoint
+	UpdateCategory       endpoint.Endpoint
+	DeleteCategory       endpoint.Endpoint
+
+	CreateReservation  endpoint.Endpoint
+	GetReservationByID endpoint.Endpoint
+	ConfirmReservation endpoint.Endpoint
+	ReleaseReservation endpoint.Endpoint
+
+	AdjustStock       endpoint.Endpoint
+	GetStockMovements endpoint.Endpoint
+
+	ListWarehouses   endpoint.Endpoint
+	GetWarehouseByID endpoint.Endpoint
+	CreateWarehouse  endpoint.Endpoint
+	UpdateWarehouse  endpoint.Endpoint
+
+	ListWebhooks        endpoint.Endpoint
+	GetWebhookByID      endpoint.Endpoint
+	CreateWebhook       endpoint.Endpoint
+	UpdateWebhook       endpoint.Endpoint
+	DeleteWebhook       endpoint.Endpoint
+	GetDeadLetters      endpoint.Endpoint
+	RedeliverDeadLetter endpoint.Endpoint
+
+	CreateImportJob    endpoint.Endpoint
+	GetImportJobByID   endpoint.Endpoint
+	GetImportJobErrors endpoint.Endpoint
+
+	ListPriceLists   endpoint.Endpoint
+	GetPriceListByID endpoint.Endpoint
+	CreatePriceList  endpoint.Endpoint
+	DeletePriceList  endpoint.Endpoint
+	SetPrice         endpoint.Endpoint
+	DeletePrice      endpoint.Endpoint
+
+	ListScheduledPrices  endpoint.Endpoint
+	SchedulePrice        endpoint.Endpoint
+	DeleteScheduledPrice endpoint.Endpoint
+	GetPriceHistory      endpoint.Endpoint
 }
 
-func MakeEndpoints(s application.Service) Endpoints {
+func MakeEndpoints(s application.Service, cs application.CategoryService, rs application.ReservationService, ss application.StockService, ws application.WarehouseService, whs application.WebhookService, ijs application.ImportJobService, pls application.PriceListService, feed MerchantFeed, cursorSecret []byte) Endpoints {
 	return Endpoints{
-		ListProducts:   makeListProductsEndpoint(s),
-		GetProductByID: makeGetProductByIDEndpoint(s),
-		CreateProduct:  makeCreateProductEndpoint(s),
+		ListProducts:     makeListProductsEndpoint(s, cursorCodec{secret: cursorSecret}),
+		GetProductByID:   makeGetProductByIDEndpoint(s),
+		GetProductBySKU:  makeGetProductBySKUEndpoint(s),
+		BatchGetProducts: makeBatchGetProductsEndpoint(s),
+		GetFacets:        makeGetFacetsEndpoint(s),
+		CreateProduct:    makeCreateProductEndpoint(s),
+		UpdateProduct:    makeUpdateProductEndpoint(s),
+		PatchProduct:     makePatchProductEndpoint(s),
+		DeleteProduct:    makeDeleteProductEndpoint(s),
+		ImportProducts:   makeImportProductsEndpoint(s),
+		ExportProducts:   makeExportProductsEndpoint(s, feed),
+
+		GetProductCategories: makeGetProductCategoriesEndpoint(cs),
+		SetProductCategories: makeSetProductCategoriesEndpoint(cs),
+		GetCategoryTree:      makeGetCategoryTreeEndpoint(cs),
+		GetCategoryByID:      makeGetCategoryByIDEndpoint(cs),
+		CreateCategory:       makeCreateCategoryEndpoint(cs),
+		UpdateCategory:       makeUpdateCategoryEndpoint(cs),
+		DeleteCategory:       makeDeleteCategoryEndpoint(cs),
+
+		CreateReservation:  makeCreateReservationEndpoint(rs),
+		GetReservationByID: makeGetReservationByIDEndpoint(rs),
+		ConfirmReservation: makeConfirmReservationEndpoint(rs),
+		ReleaseReservation: makeReleaseReservationEndpoint(rs),
+
+		AdjustStock:       makeAdjustStockEndpoint(ss),
+		GetStockMovements: makeGetStockMovementsEndpoint(ss),
+
+		ListWarehouses:   makeListWarehousesEndpoint(ws),
+		GetWarehouseByID: makeGetWarehouseByIDEndpoint(ws),
+		CreateWarehouse:  makeCreateWarehouseEndpoint(ws),
+		UpdateWarehouse:  makeUpdateWarehouseEndpoint(ws),
+
+		ListWebhooks:        makeListWebhooksEndpoint(whs),
+		GetWebhookByID:      makeGetWebhookByIDEndpoint(whs),
+		CreateWebhook:       makeCreateWebhookEndpoint(whs),
+		UpdateWebhook:       makeUpdateWebhookEndpoint(whs),
+		DeleteWebhook:       makeDeleteWebhookEndpoint(whs),
+		GetDeadLetters:      makeGetDeadLettersEndpoint(whs),
+		RedeliverDeadLetter: makeRedeliverDeadLetterEndpoint(whs),
+
+		CreateImportJob:    makeCreateImportJobEndpoint(ijs),
+		GetImportJobByID:   makeGetImportJobByIDEndpoint(ijs),
+		GetImportJobErrors: makeGetImportJobErrorsEndpoint(ijs),
+
+		ListPriceLists:   makeListPriceListsEndpoint(pls),
+		GetPriceListByID: makeGetPriceListByIDEndpoint(pls),
+		CreatePriceList:  makeCreatePriceListEndpoint(pls),
+		DeletePriceList:  makeDeletePriceListEndpoint(pls),
+		SetPrice:         makeSetPriceEndpoint(pls),
+		DeletePrice:      makeDeletePriceEndpoint(pls),
+
+		ListScheduledPrices:  makeListScheduledPricesEndpoint(pls),
+		SchedulePrice:        makeSchedulePriceEndpoint(pls),
+		DeleteScheduledPrice: makeDeleteScheduledPriceEndpoint(pls),
+		GetPriceHistory:      makeGetPriceHistoryEndpoint(s),
 	}
 }
 
-func makeListProductsEndpoint(s application.Service) endpoint.Endpoint {
+func makeListProductsEndpoint(s application.Service, codec cursorCodec) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(*listProductsRequest)
-		items, err := s.Find(req.PageSpec, req.Filters)
+		if req.After != "" {
+			cursor, err := codec.decode(req.After, req.PageSpec.Sort)
+			if err != nil {
+				return nil, err
+			}
+			req.PageSpec.After = cursor
+		}
+		page, err := s.Find(req.PageSpec, req.Filters)
 		if err != nil {
 			return nil, err
 		}
-		count := len(items)
+		after, err := codec.encode(page.Next, req.PageSpec.Sort)
+		if err != nil {
+			return nil, err
+		}
+		count := len(page.Items)
 		products := make([]*product, count)
-		for i, item := range items {
+		for i, item := range page.Items {
 			products[i] = toProduct(item)
 		}
 		res := &listProductsResponse{
-			Items: products,
-			Count: count,
+			Items:   products,
+			After:   after,
+			Count:   count,
+			Total:   page.Total,
+			HasNext: page.HasNext,
+			HasPrev: page.HasPrev,
+			links:   pageLinks(req.Query, req.PageSpec, page, after),
 		}
 		return res, nil
 	}
@@ -45,19 +167,125 @@ func makeListProductsEndpoint(s application.Service) endpoint.Endpoint {
 
 func makeGetProductByIDEndpoint(s application.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		id := request.(*uuid.UUID)
-		item, err := s.FindByID(*id)
+		req := request.(*getProductByIDRequest)
+		item, err := s.FindByID(req.ID, req.Pricing)
+		if err != nil {
+			return nil, err
+		}
+		etag := formatProductETag(item)
+		if etagMatches(req.IfNoneMatch, etag) {
+			return &notModifiedResponse{etag: etag}, nil
+		}
+		return &getProductByIDResponse{product: *toProduct(item), etag: etag}, nil
+	}
+}
+
+func makeGetProductBySKUEndpoint(s application.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*getProductBySKURequest)
+		item, err := s.FindBySKU(req.SKU, req.Pricing)
+		if err != nil {
+			return nil, err
+		}
+		etag := formatProductETag(item)
+		if etagMatches(req.IfNoneMatch, etag) {
+			return &notModifiedResponse{etag: etag}, nil
+		}
+		return &getProductByIDResponse{product: *toProduct(item), etag: etag}, nil
+	}
+}
+
+func makeBatchGetProductsEndpoint(s application.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*batchGetProductsRequest)
+		batch, err := s.FindBatch(req.IDs, req.SKUs, req.Pricing)
+		if err != nil {
+			return nil, err
+		}
+		res := &batchGetProductsResponse{
+			Items:       make([]*product, len(batch.Items)),
+			MissingIDs:  make([]string, len(batch.MissingIDs)),
+			MissingSKUs: append([]string{}, batch.MissingSKUs...),
+		}
+		for i, item := range batch.Items {
+			res.Items[i] = toProduct(item)
+		}
+		for i, id := range batch.MissingIDs {
+			res.MissingIDs[i] = id.String()
+		}
+		return res, nil
+	}
+}
+
+// makeExportProductsEndpoint defers reading the products to the response encoder, so that they are streamed
+// to the client.
+func makeExportProductsEndpoint(s application.Service, feed MerchantFeed) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*exportProductsRequest)
+		return &exportProductsResponse{
+			Format: req.Format,
+			Gzip:   req.Gzip,
+			Feed:   feed,
+			Export: func(write func(items []*application.Product) error) error {
+				return s.Export(req.Filters, req.Sort, write)
+			},
+		}, nil
+	}
+}
+
+func makeImportProductsEndpoint(s application.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*importProductsRequest)
+		report, err := s.Import(req.Rows, req.Actor)
 		if err != nil {
 			return nil, err
 		}
-		return &getProductByIDResponse{*toProduct(item)}, nil
+		res := &importProductsResponse{
+			Created: report.Created,
+			Updated: report.Updated,
+			Failed:  report.Failed,
+			Rows:    make([]*importRowResult, len(report.Rows)),
+		}
+		for i, row := range report.Rows {
+			result := &importRowResult{Row: row.Number, SKU: row.SKU, Status: string(row.Status)}
+			if row.ID != nil {
+				result.ID = row.ID.String()
+			}
+			if row.Err != nil {
+				errorResponse := translateError(row.Err).Response
+				result.Error = &errorResponse
+			}
+			res.Rows[i] = result
+		}
+		return res, nil
+	}
+}
+
+func makeGetFacetsEndpoint(s application.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*productFacetsRequest)
+		facets, err := s.Facets(req.Filters, req.Spec)
+		if err != nil {
+			return nil, err
+		}
+		res := &productFacetsResponse{}
+		for _, v := range facets.Color {
+			res.Color = append(res.Color, facetValue{Value: v.Value, Count: v.Count})
+		}
+		for _, v := range facets.Material {
+			res.Material = append(res.Material, facetValue{Value: v.Value, Count: v.Count})
+		}
+		for _, b := range facets.Price {
+			res.Price = append(res.Price, priceBucket{From: b.From, To: b.To, Count: b.Count})
+		}
+		return res, nil
 	}
 }
 
 func makeCreateProductEndpoint(s application.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(*createProductRequest)
-		id, err := s.Create(req)
+		id, err := s.Create(req, req.Actor)
 		if err != nil {
 			return nil, err
 		}
@@ -65,19 +293,79 @@ func makeCreateProductEndpoint(s application.Service) endpoint.Endpoint {
 	}
 }
 
+func makeUpdateProductEndpoint(s application.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*updateProductRequest)
+		if _, err := s.Update(req.ID, req.Versions, req, req.Actor); err != nil {
+			return nil, err
+		}
+		return readWrittenProduct(s, req.ID)
+	}
+}
+
+func makePatchProductEndpoint(s application.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*patchProductRequest)
+		if _, err := s.Patch(req.ID, req.Versions, req.Patch, req.Actor); err != nil {
+			return nil, err
+		}
+		return readWrittenProduct(s, req.ID)
+	}
+}
+
+// readWrittenProduct responds to a write with the product as GET reads it without a price selection,
+// so the ETag of the response is usable in If-None-Match.
+func readWrittenProduct(s application.Service, id uuid.UUID) (interface{}, error) {
+	item, err := s.FindByID(id, &application.PriceSelection{})
+	if err != nil {
+		return nil, err
+	}
+	return &updateProductResponse{product: *toProduct(item), etag: formatProductETag(item)}, nil
+}
+
+func makeDeleteProductEndpoint(s application.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*deleteProductRequest)
+		if err := s.Delete(req.ID, req.Versions); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+}
+
 func toProduct(item *application.Product) *product {
-	return &product{
-		ID:           item.ID.String(),
-		Title:        item.Title,
-		SKU:          item.SKU,
-		Price:        item.Price,
-		AvailableQty: item.AvailableQty,
+	result := &product{
+		ID:             item.ID.String(),
+		Title:          item.Title,
+		SKU:            item.SKU,
+		Price:          item.Price,
+		CompareAtPrice: item.CompareAtPrice,
+		Currency:       item.Currency,
+		AvailableQty:   item.AvailableQty,
 		Image: image{
 			URL:    item.Image.URL,
 			Width:  &item.Image.Width,
 			Height: &item.Image.Height,
 		},
-		Color:    item.Color,
-		Material: item.Material,
+		Color:       item.Color,
+		Material:    item.Material,
+		VariantAxes: item.VariantAxes,
+		Options:     item.Options,
+		Relevance:   item.Relevance,
+		Highlight:   item.Highlight,
+	}
+	if item.ParentID != nil {
+		parentID := item.ParentID.String()
+		result.ParentID = &parentID
+	}
+	if item.PriceRange != nil {
+		result.PriceRange = &priceRange{Min: item.PriceRange.Min, Max: item.PriceRange.Max}
+	}
+	for _, stock := range item.Stock {
+		result.Stock = append(result.Stock, warehouseStock{WarehouseID: stock.WarehouseID.String(), AvailableQty: stock.Quantity})
+	}
+	for _, variant := range item.Variants {
+		result.Variants = append(result.Variants, toProduct(variant))
 	}
+	return result
 }
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+const anyETag = "*"
+
+var ErrPreconditionRequired = errors.New("precondition required: missing 'If-Match' header")
+
+// formatProductETag tags the product read with the prices and stock it was read with, prices change over time
+// without a new product version as scheduled prices start and end, and stock movements keep the version.
+func formatProductETag(item *application.Product) string {
+	h := fnv.New32a()
+	for _, p := range append([]*application.Product{item}, item.Variants...) {
+		_, _ = fmt.Fprintf(h, "%s %s", p.Price.String(), p.Currency)
+		if p.CompareAtPrice != nil {
+			_, _ = fmt.Fprintf(h, " %s", p.CompareAtPrice.String())
+		}
+		_, _ = fmt.Fprintf(h, " %d", p.AvailableQty)
+		for _, stock := range p.Stock {
+			_, _ = fmt.Fprintf(h, " %s=%d", stock.WarehouseID.String(), stock.Quantity)
+		}
+		_, _ = h.Write([]byte{';'})
+	}
+	return fmt.Sprintf(`"%d.%08x"`, item.Version, h.Sum32())
+}
+
+// parseIfMatch returns the product versions listed in the If-Match header, nil means any version.
+// If-Match uses strong comparison, so weak tags never match.
+func parseIfMatch(r *http.Request) ([]int, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" {
+		return nil, ErrPreconditionRequired
+	}
+	var versions []int
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == anyETag {
+			return nil, nil
+		}
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		tag = strings.Trim(tag, `"`)
+		if i := strings.IndexByte(tag, '.'); i >= 0 {
+			tag = tag[:i]
+		}
+		version, err := strconv.Atoi(tag)
+		if err != nil {
+			return nil, errors.Wrap(ErrBadRequest, "invalid 'If-Match' header")
+		}
+		versions = append(versions, version)
+	}
+	if versions == nil {
+		return nil, application.ErrVersionMismatch
+	}
+	return versions, nil
+}
+
+// etagMatches reports whether the If-None-Match header value matches the entity tag using weak comparison.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == anyETag || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	// ExportFormatMerchant is the RSS 2.0 product feed of Google Merchant Center
+	ExportFormatMerchant = "google"
+
+	merchantNamespace = "http://base.google.com/ns/1.0"
+)
+
+// MerchantFeed describes the shop in Google Merchant Center feeds.
+type MerchantFeed struct {
+	Title       string
+	Link        string
+	Description string
+	// ProductURL is the template of product page links, {sku} and {id} are replaced with the escaped values
+	ProductURL string
+}
+
+type exportFormat struct {
+	contentType string
+	extension   string
+	newWriter   func(w io.Writer, feed MerchantFeed) productWriter
+}
+
+var exportFormats = map[string]exportFormat{
+	ExportFormatCSV: {
+		contentType: "text/csv; charset=utf-8",
+		extension:   "csv",
+		newWriter: func(w io.Writer, _ MerchantFeed) productWriter {
+			return &csvProductWriter{writer: csv.NewWriter(w)}
+		},
+	},
+	ExportFormatNDJSON: {
+		contentType: "application/x-ndjson",
+		extension:   "ndjson",
+		newWriter: func(w io.Writer, _ MerchantFeed) productWriter {
+			return &ndjsonProductWriter{encoder: json.NewEncoder(w)}
+		},
+	},
+	ExportFormatMerchant: {
+		contentType: "application/rss+xml; charset=utf-8",
+		extension:   "xml",
+		newWriter: func(w io.Writer, feed MerchantFeed) productWriter {
+			return &merchantProductWriter{writer: w, encoder: xml.NewEncoder(w), feed: feed}
+		},
+	},
+}
+
+// productWriter writes an export file: begin and end frame it, write is called for every page of products.
+type productWriter interface {
+	begin() error
+	write(items []*application.Product) error
+	end() error
+}
+
+type csvProductWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvProductWriter) begin() error {
+	return w.writer.Write(productCSVColumns)
+}
+
+func (w *csvProductWriter) write(items []*application.Product) error {
+	for _, item := range items {
+		var parentID string
+		if item.ParentID != nil {
+			parentID = item.ParentID.String()
+		}
+		var imageURL, imageWidth, imageHeight string
+		if item.Image != nil {
+			imageURL = item.Image.URL
+			imageWidth = strconv.Itoa(item.Image.Width)
+			imageHeight = strconv.Itoa(item.Image.Height)
+		}
+		options := make([]string, 0, len(item.Options))
+		for axis, value := range item.Options {
+			options = append(options, axis+"="+value)
+		}
+		sort.Strings(options)
+		err := w.writer.Write([]string{
+			item.ID.String(),
+			item.Title,
+			item.SKU,
+			item.Price.String(),
+			item.Currency,
+			strconv.Itoa(item.AvailableQty),
+			imageURL,
+			imageWidth,
+			imageHeight,
+			item.Color,
+			item.Material,
+			parentID,
+			strings.Join(item.VariantAxes, importListSeparator),
+			strings.Join(options, importListSeparator),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvProductWriter) end() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type ndjsonProductWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonProductWriter) begin() error {
+	return nil
+}
+
+func (w *ndjsonProductWriter) write(items []*application.Product) error {
+	for _, item := range items {
+		if err := w.encoder.Encode(toProduct(item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *ndjsonProductWriter) end() error {
+	return nil
+}
+
+type merchantItem struct {
+	XMLName          xml.Name `xml:"item"`
+	ID               string   `xml:"g:id"`
+	Title            string   `xml:"g:title"`
+	Description      string   `xml:"g:description"`
+	Link             string   `xml:"g:link,omitempty"`
+	ImageLink        string   `xml:"g:image_link"`
+	Availability     string   `xml:"g:availability"`
+	Price            string   `xml:"g:price"`
+	Condition        string   `xml:"g:condition"`
+	Color            string   `xml:"g:color,omitempty"`
+	Material         string   `xml:"g:material,omitempty"`
+	ItemGroupID      string   `xml:"g:item_group_id,omitempty"`
+	IdentifierExists string   `xml:"g:identifier_exists"`
+}
+
+// merchantProductWriter writes an RSS 2.0 feed with the Google Merchant Center namespace. Products are
+// identified by SKU, variants are grouped by the id of their parent product.
+type merchantProductWriter struct {
+	writer  io.Writer
+	encoder *xml.Encoder
+	feed    MerchantFeed
+}
+
+func (w *merchantProductWriter) begin() error {
+	_, err := io.WriteString(w.writer, xml.Header+`<rss version="2.0" xmlns:g="`+merchantNamespace+`"><channel>`)
+	if err != nil {
+		return err
+	}
+	for _, element := range []struct{ name, value string }{
+		{"title", w.feed.Title},
+		{"link", w.feed.Link},
+		{"description", w.feed.Description},
+	} {
+		if err = w.encoder.EncodeElement(element.value, xml.StartElement{Name: xml.Name{Local: element.name}}); err != nil {
+			return err
+		}
+	}
+	return w.encoder.Flush()
+}
+
+func (w *merchantProductWriter) write(items []*application.Product) error {
+	for _, item := range items {
+		availability := "out_of_stock"
+		if item.AvailableQty > 0 {
+			availability = "in_stock"
+		}
+		feedItem := merchantItem{
+			ID:               item.SKU,
+			Title:            item.Title,
+			Description:      item.Title,
+			Link:             w.productURL(item),
+			Availability:     availability,
+			Price:            item.Price.StringFixed(2) + " " + item.Currency,
+			Condition:        "new",
+			Color:            item.Color,
+			Material:         item.Material,
+			IdentifierExists: "no",
+		}
+		if item.Image != nil {
+			feedItem.ImageLink = item.Image.URL
+		}
+		if item.ParentID != nil {
+			feedItem.ItemGroupID = item.ParentID.String()
+		}
+		if err := w.encoder.Encode(feedItem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *merchantProductWriter) end() error {
+	_, err := io.WriteString(w.writer, "</channel></rss>\n")
+	return err
+}
+
+func (w *merchantProductWriter) productURL(item *application.Product) string {
+	if w.feed.ProductURL == "" {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{sku}", url.PathEscape(item.SKU),
+		"{id}", item.ID.String(),
+	).Replace(w.feed.ProductURL)
+}
+
+// acceptsGzip tells whether the Accept-Encoding header allows a gzip encoded response.
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(part, ";")
+		if strings.TrimSpace(params[0]) != "gzip" {
+			continue
+		}
+		for _, param := range params[1:] {
+			if q := strings.TrimSpace(param); strings.HasPrefix(q, "q=") {
+				if weight, err := strconv.ParseFloat(q[2:], 64); err == nil && weight == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
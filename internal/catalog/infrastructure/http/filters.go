@@ -2,8 +2,10 @@ package http
 
 import (
 	"net/url"
+	"strconv"
 	"strings"
 
+	"github.com/jnikolaeva/eshop-common/uuid"
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 
@@ -12,6 +14,14 @@ import (
 
 const valuesSeparator = ","
 
+var defaultPriceBuckets = []decimal.Decimal{
+	decimal.NewFromInt(25),
+	decimal.NewFromInt(50),
+	decimal.NewFromInt(100),
+	decimal.NewFromInt(250),
+	decimal.NewFromInt(500),
+}
+
 type parser func(value string, filter interface{}) error
 
 func parseFilter(values url.Values, paramName string, parser parser, filter interface{}) error {
@@ -62,3 +72,91 @@ func parseDecimalRangeFilter(value string, filter interface{}) error {
 	}
 	return nil
 }
+
+func parseCategoryFilter(values url.Values) (*application.CategoryFilter, error) {
+	value := values.Get("category")
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.FromString(value)
+	if err != nil {
+		return nil, errors.New("can't parse category id for the filter")
+	}
+	filter := &application.CategoryFilter{ID: application.CategoryID(id)}
+	if value := values.Get("include_subcategories"); value != "" {
+		if filter.IncludeDescendants, err = strconv.ParseBool(value); err != nil {
+			return nil, errors.New("can't parse include_subcategories value for the filter")
+		}
+	}
+	return filter, nil
+}
+
+func parseWarehouseFilter(values url.Values) (warehouseID *application.WarehouseID, inStock *bool, err error) {
+	if value := values.Get("warehouse"); value != "" {
+		id, err := uuid.FromString(value)
+		if err != nil {
+			return nil, nil, errors.New("can't parse warehouse id for the filter")
+		}
+		warehouseID = (*application.WarehouseID)(&id)
+	}
+	if value := values.Get("in_stock"); value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, nil, errors.New("can't parse in_stock value for the filter")
+		}
+		inStock = &b
+	}
+	return warehouseID, inStock, nil
+}
+
+func parseSortSpec(values url.Values) (application.SortSpec, error) {
+	value := values.Get("sort")
+	if value == "" {
+		return nil, nil
+	}
+	var spec application.SortSpec
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(value, valuesSeparator) {
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+		if seen[field] {
+			return nil, errors.Errorf("duplicate sort field '%s'", field)
+		}
+		seen[field] = true
+		order, err := application.NewSortOrder(field, desc)
+		if err != nil {
+			return nil, err
+		}
+		spec = append(spec, order)
+	}
+	return spec, nil
+}
+
+func parseFacetSpec(values url.Values) (application.FacetSpec, error) {
+	spec := application.FacetSpec{PriceBuckets: defaultPriceBuckets}
+	value := values.Get("facets")
+	if value == "" {
+		return spec, errors.New("missing required parameter 'facets'")
+	}
+	for _, field := range strings.Split(value, valuesSeparator) {
+		f, err := application.NewFacetField(field)
+		if err != nil {
+			return spec, err
+		}
+		spec.Fields = append(spec.Fields, f)
+	}
+	if value := values.Get("price_buckets"); value != "" {
+		spec.PriceBuckets = nil
+		for _, bound := range strings.Split(value, valuesSeparator) {
+			b, err := decimal.NewFromString(bound)
+			if err != nil {
+				return spec, errors.New("can't parse price bucket bound")
+			}
+			if n := len(spec.PriceBuckets); n > 0 && !b.GreaterThan(spec.PriceBuckets[n-1]) {
+				return spec, errors.New("price bucket bounds must be ascending")
+			}
+			spec.PriceBuckets = append(spec.PriceBuckets, b)
+		}
+	}
+	return spec, nil
+}
@@ -1,13 +1,17 @@
 package http
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
 	gokittransport "github.com/go-kit/kit/transport"
 	gokithttp "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
@@ -19,7 +23,14 @@ import (
 	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
 )
 
-const defaultPageSize = 10
+const (
+	defaultPageSize = 10
+	// maxBatchSize limits the number of ids and SKUs of one batch get
+	maxBatchSize = 100
+
+	// actorHeader identifies the user or system on whose behalf stock is changed
+	actorHeader = "X-Actor"
+)
 
 var (
 	ErrBadRouting = errors.New("bad routing")
@@ -33,19 +44,121 @@ func MakeHandler(pathPrefix string, endpoints Endpoints, errorLogger log.Logger,
 	}
 
 	listProductsHandler := gokithttp.NewServer(endpoints.ListProducts, decodeListProductsRequest, encodeResponse, options...)
+	batchGetProductsQueryHandler := gokithttp.NewServer(endpoints.BatchGetProducts, decodeBatchGetProductsQuery, encodeResponse, options...)
+	batchGetProductsHandler := gokithttp.NewServer(endpoints.BatchGetProducts, decodeBatchGetProductsRequest, encodeResponse, options...)
+	getProductFacetsHandler := gokithttp.NewServer(endpoints.GetFacets, decodeProductFacetsRequest, encodeResponse, options...)
+	getProductBySKUHandler := gokithttp.NewServer(endpoints.GetProductBySKU, decodeGetProductBySKURequest, encodeResponse, options...)
 	getProductByIDHandler := gokithttp.NewServer(endpoints.GetProductByID, decodeGetProductByIDRequest, encodeResponse, options...)
 	createProductHandler := gokithttp.NewServer(endpoints.CreateProduct, decodeCreateProductRequest, encodeResponse, options...)
+	updateProductHandler := gokithttp.NewServer(endpoints.UpdateProduct, decodeUpdateProductRequest, encodeResponse, options...)
+	patchProductHandler := gokithttp.NewServer(endpoints.PatchProduct, decodePatchProductRequest, encodeResponse, options...)
+	deleteProductHandler := gokithttp.NewServer(endpoints.DeleteProduct, decodeDeleteProductRequest, encodeResponse, options...)
+	importProductsHandler := gokithttp.NewServer(endpoints.ImportProducts, decodeImportProductsRequest, encodeResponse, options...)
+	exportProductsHandler := gokithttp.NewServer(endpoints.ExportProducts, decodeExportProductsRequest, makeExportResponseEncoder(errorLogger), options...)
+	getProductCategoriesHandler := gokithttp.NewServer(endpoints.GetProductCategories, decodePathIDRequest, encodeResponse, options...)
+	setProductCategoriesHandler := gokithttp.NewServer(endpoints.SetProductCategories, decodeSetProductCategoriesRequest, encodeResponse, options...)
+	getCategoryTreeHandler := gokithttp.NewServer(endpoints.GetCategoryTree, gokithttp.NopRequestDecoder, encodeResponse, options...)
+	getCategoryByIDHandler := gokithttp.NewServer(endpoints.GetCategoryByID, decodePathIDRequest, encodeResponse, options...)
+	createCategoryHandler := gokithttp.NewServer(endpoints.CreateCategory, decodeCreateCategoryRequest, encodeResponse, options...)
+	updateCategoryHandler := gokithttp.NewServer(endpoints.UpdateCategory, decodeUpdateCategoryRequest, encodeResponse, options...)
+	deleteCategoryHandler := gokithttp.NewServer(endpoints.DeleteCategory, decodePathIDRequest, encodeResponse, options...)
+	reserveProductHandler := gokithttp.NewServer(endpoints.CreateReservation, decodeReserveProductRequest, encodeResponse, options...)
+	createReservationHandler := gokithttp.NewServer(endpoints.CreateReservation, decodeCreateReservationRequest, encodeResponse, options...)
+	getReservationByIDHandler := gokithttp.NewServer(endpoints.GetReservationByID, decodePathIDRequest, encodeResponse, options...)
+	confirmReservationHandler := gokithttp.NewServer(endpoints.ConfirmReservation, decodePathIDRequest, encodeResponse, options...)
+	releaseReservationHandler := gokithttp.NewServer(endpoints.ReleaseReservation, decodePathIDRequest, encodeResponse, options...)
+	listWarehousesHandler := gokithttp.NewServer(endpoints.ListWarehouses, gokithttp.NopRequestDecoder, encodeResponse, options...)
+	getWarehouseByIDHandler := gokithttp.NewServer(endpoints.GetWarehouseByID, decodePathIDRequest, encodeResponse, options...)
+	createWarehouseHandler := gokithttp.NewServer(endpoints.CreateWarehouse, decodeCreateWarehouseRequest, encodeResponse, options...)
+	updateWarehouseHandler := gokithttp.NewServer(endpoints.UpdateWarehouse, decodeUpdateWarehouseRequest, encodeResponse, options...)
+	listWebhooksHandler := gokithttp.NewServer(endpoints.ListWebhooks, gokithttp.NopRequestDecoder, encodeResponse, options...)
+	getWebhookByIDHandler := gokithttp.NewServer(endpoints.GetWebhookByID, decodePathIDRequest, encodeResponse, options...)
+	createWebhookHandler := gokithttp.NewServer(endpoints.CreateWebhook, decodeCreateWebhookRequest, encodeResponse, options...)
+	updateWebhookHandler := gokithttp.NewServer(endpoints.UpdateWebhook, decodeUpdateWebhookRequest, encodeResponse, options...)
+	deleteWebhookHandler := gokithttp.NewServer(endpoints.DeleteWebhook, decodePathIDRequest, encodeResponse, options...)
+	getDeadLettersHandler := gokithttp.NewServer(endpoints.GetDeadLetters, decodeDeadLettersRequest, encodeResponse, options...)
+	redeliverDeadLetterHandler := gokithttp.NewServer(endpoints.RedeliverDeadLetter, decodeDeadLetterIDRequest, encodeResponse, options...)
+	createImportJobHandler := gokithttp.NewServer(endpoints.CreateImportJob, decodeCreateImportJobRequest, encodeResponse, options...)
+	getImportJobByIDHandler := gokithttp.NewServer(endpoints.GetImportJobByID, decodePathIDRequest, encodeResponse, options...)
+	getImportJobErrorsHandler := gokithttp.NewServer(endpoints.GetImportJobErrors, decodePathIDRequest, encodeImportJobErrorsResponse, options...)
+	listPriceListsHandler := gokithttp.NewServer(endpoints.ListPriceLists, gokithttp.NopRequestDecoder, encodeResponse, options...)
+	getPriceListByIDHandler := gokithttp.NewServer(endpoints.GetPriceListByID, decodePathIDRequest, encodeResponse, options...)
+	createPriceListHandler := gokithttp.NewServer(endpoints.CreatePriceList, decodeCreatePriceListRequest, encodeResponse, options...)
+	deletePriceListHandler := gokithttp.NewServer(endpoints.DeletePriceList, decodeDeletePriceListRequest, encodeResponse, options...)
+	setPriceHandler := gokithttp.NewServer(endpoints.SetPrice, decodeSetPriceRequest, encodeResponse, options...)
+	deletePriceHandler := gokithttp.NewServer(endpoints.DeletePrice, decodePriceListEntryRequest, encodeResponse, options...)
+	listScheduledPricesHandler := gokithttp.NewServer(endpoints.ListScheduledPrices, decodePathIDRequest, encodeResponse, options...)
+	schedulePriceHandler := gokithttp.NewServer(endpoints.SchedulePrice, decodeSchedulePriceRequest, encodeResponse, options...)
+	deleteScheduledPriceHandler := gokithttp.NewServer(endpoints.DeleteScheduledPrice, decodeScheduledPriceRequest, encodeResponse, options...)
+	getPriceHistoryHandler := gokithttp.NewServer(endpoints.GetPriceHistory, decodePriceHistoryRequest, encodeResponse, options...)
+	getPriceHistoryBySKUHandler := gokithttp.NewServer(endpoints.GetPriceHistory, decodePriceHistoryBySKURequest, encodeResponse, options...)
+	adjustStockHandler := gokithttp.NewServer(endpoints.AdjustStock, decodeStockAdjustmentRequest, encodeResponse, options...)
+	getStockMovementsHandler := gokithttp.NewServer(endpoints.GetStockMovements, decodeStockMovementsRequest, encodeResponse, options...)
 
 	r := mux.NewRouter()
 	s := r.PathPrefix(pathPrefix).Subrouter()
+	s.Handle("/products", httpkit.InstrumentingMiddleware(batchGetProductsQueryHandler, metrics, "BatchGetProducts")).Methods(http.MethodGet).Queries("ids", "{ids}")
+	s.Handle("/products", httpkit.InstrumentingMiddleware(batchGetProductsQueryHandler, metrics, "BatchGetProducts")).Methods(http.MethodGet).Queries("skus", "{skus}")
+	s.Handle("/products:batchGet", httpkit.InstrumentingMiddleware(batchGetProductsHandler, metrics, "BatchGetProducts")).Methods(http.MethodPost)
+	s.Handle("/products:export", httpkit.InstrumentingMiddleware(exportProductsHandler, metrics, "ExportProducts")).Methods(http.MethodGet)
+	s.Handle("/products:import", httpkit.InstrumentingMiddleware(importProductsHandler, metrics, "ImportProducts")).Methods(http.MethodPost)
 	s.Handle("/products", httpkit.InstrumentingMiddleware(listProductsHandler, metrics, "ListProducts")).Methods(http.MethodGet)
 	s.Handle("/products", httpkit.InstrumentingMiddleware(createProductHandler, metrics, "CreateProduct")).Methods(http.MethodPost)
+	s.Handle("/products/facets", httpkit.InstrumentingMiddleware(getProductFacetsHandler, metrics, "GetProductFacets")).Methods(http.MethodGet)
+	s.Handle("/products/by-sku/{sku:.+}", httpkit.InstrumentingMiddleware(getProductBySKUHandler, metrics, "GetProductBySKU")).Methods(http.MethodGet)
 	s.Handle("/products/{id}", httpkit.InstrumentingMiddleware(getProductByIDHandler, metrics, "GetProductByID")).Methods(http.MethodGet)
+	s.Handle("/products/{id}", httpkit.InstrumentingMiddleware(updateProductHandler, metrics, "UpdateProduct")).Methods(http.MethodPut)
+	s.Handle("/products/{id}", httpkit.InstrumentingMiddleware(patchProductHandler, metrics, "PatchProduct")).Methods(http.MethodPatch)
+	s.Handle("/products/{id}", httpkit.InstrumentingMiddleware(deleteProductHandler, metrics, "DeleteProduct")).Methods(http.MethodDelete)
+	s.Handle("/products/{id}/categories", httpkit.InstrumentingMiddleware(getProductCategoriesHandler, metrics, "GetProductCategories")).Methods(http.MethodGet)
+	s.Handle("/products/{id}/categories", httpkit.InstrumentingMiddleware(setProductCategoriesHandler, metrics, "SetProductCategories")).Methods(http.MethodPut)
+	s.Handle("/products/{id}/reservations", httpkit.InstrumentingMiddleware(reserveProductHandler, metrics, "ReserveProduct")).Methods(http.MethodPost)
+	s.Handle("/products/{id}/stock-adjustments", httpkit.InstrumentingMiddleware(adjustStockHandler, metrics, "AdjustStock")).Methods(http.MethodPost)
+	s.Handle("/products/{id}/stock-movements", httpkit.InstrumentingMiddleware(getStockMovementsHandler, metrics, "GetStockMovements")).Methods(http.MethodGet)
+	s.Handle("/products/{id}/scheduled-prices", httpkit.InstrumentingMiddleware(listScheduledPricesHandler, metrics, "ListScheduledPrices")).Methods(http.MethodGet)
+	s.Handle("/products/{id}/scheduled-prices", httpkit.InstrumentingMiddleware(schedulePriceHandler, metrics, "SchedulePrice")).Methods(http.MethodPost)
+	s.Handle("/products/{id}/scheduled-prices/{price_id}", httpkit.InstrumentingMiddleware(deleteScheduledPriceHandler, metrics, "DeleteScheduledPrice")).Methods(http.MethodDelete)
+	s.Handle("/products/{id}/price-history", httpkit.InstrumentingMiddleware(getPriceHistoryHandler, metrics, "GetPriceHistory")).Methods(http.MethodGet)
+	s.Handle("/price-history", httpkit.InstrumentingMiddleware(getPriceHistoryBySKUHandler, metrics, "GetPriceHistory")).Methods(http.MethodGet)
+	s.Handle("/categories", httpkit.InstrumentingMiddleware(getCategoryTreeHandler, metrics, "GetCategoryTree")).Methods(http.MethodGet)
+	s.Handle("/categories", httpkit.InstrumentingMiddleware(createCategoryHandler, metrics, "CreateCategory")).Methods(http.MethodPost)
+	s.Handle("/categories/{id}", httpkit.InstrumentingMiddleware(getCategoryByIDHandler, metrics, "GetCategoryByID")).Methods(http.MethodGet)
+	s.Handle("/categories/{id}", httpkit.InstrumentingMiddleware(updateCategoryHandler, metrics, "UpdateCategory")).Methods(http.MethodPut)
+	s.Handle("/categories/{id}", httpkit.InstrumentingMiddleware(deleteCategoryHandler, metrics, "DeleteCategory")).Methods(http.MethodDelete)
+	s.Handle("/warehouses", httpkit.InstrumentingMiddleware(listWarehousesHandler, metrics, "ListWarehouses")).Methods(http.MethodGet)
+	s.Handle("/warehouses", httpkit.InstrumentingMiddleware(createWarehouseHandler, metrics, "CreateWarehouse")).Methods(http.MethodPost)
+	s.Handle("/warehouses/{id}", httpkit.InstrumentingMiddleware(getWarehouseByIDHandler, metrics, "GetWarehouseByID")).Methods(http.MethodGet)
+	s.Handle("/warehouses/{id}", httpkit.InstrumentingMiddleware(updateWarehouseHandler, metrics, "UpdateWarehouse")).Methods(http.MethodPut)
+	s.Handle("/webhooks", httpkit.InstrumentingMiddleware(listWebhooksHandler, metrics, "ListWebhooks")).Methods(http.MethodGet)
+	s.Handle("/webhooks", httpkit.InstrumentingMiddleware(createWebhookHandler, metrics, "CreateWebhook")).Methods(http.MethodPost)
+	s.Handle("/webhooks/dead-letters", httpkit.InstrumentingMiddleware(getDeadLettersHandler, metrics, "GetDeadLetters")).Methods(http.MethodGet)
+	s.Handle("/webhooks/dead-letters/{id}/redeliver", httpkit.InstrumentingMiddleware(redeliverDeadLetterHandler, metrics, "RedeliverDeadLetter")).Methods(http.MethodPost)
+	s.Handle("/webhooks/{id}", httpkit.InstrumentingMiddleware(getWebhookByIDHandler, metrics, "GetWebhookByID")).Methods(http.MethodGet)
+	s.Handle("/webhooks/{id}", httpkit.InstrumentingMiddleware(updateWebhookHandler, metrics, "UpdateWebhook")).Methods(http.MethodPut)
+	s.Handle("/webhooks/{id}", httpkit.InstrumentingMiddleware(deleteWebhookHandler, metrics, "DeleteWebhook")).Methods(http.MethodDelete)
+	s.Handle("/imports", httpkit.InstrumentingMiddleware(createImportJobHandler, metrics, "CreateImportJob")).Methods(http.MethodPost)
+	s.Handle("/imports/{id}", httpkit.InstrumentingMiddleware(getImportJobByIDHandler, metrics, "GetImportJobByID")).Methods(http.MethodGet)
+	s.Handle("/imports/{id}/errors", httpkit.InstrumentingMiddleware(getImportJobErrorsHandler, metrics, "GetImportJobErrors")).Methods(http.MethodGet)
+	s.Handle("/price-lists", httpkit.InstrumentingMiddleware(listPriceListsHandler, metrics, "ListPriceLists")).Methods(http.MethodGet)
+	s.Handle("/price-lists", httpkit.InstrumentingMiddleware(createPriceListHandler, metrics, "CreatePriceList")).Methods(http.MethodPost)
+	s.Handle("/price-lists/{id}", httpkit.InstrumentingMiddleware(getPriceListByIDHandler, metrics, "GetPriceListByID")).Methods(http.MethodGet)
+	s.Handle("/price-lists/{id}", httpkit.InstrumentingMiddleware(deletePriceListHandler, metrics, "DeletePriceList")).Methods(http.MethodDelete)
+	s.Handle("/price-lists/{id}/prices/{product_id}", httpkit.InstrumentingMiddleware(setPriceHandler, metrics, "SetPrice")).Methods(http.MethodPut)
+	s.Handle("/price-lists/{id}/prices/{product_id}", httpkit.InstrumentingMiddleware(deletePriceHandler, metrics, "DeletePrice")).Methods(http.MethodDelete)
+	s.Handle("/reservations", httpkit.InstrumentingMiddleware(createReservationHandler, metrics, "CreateReservation")).Methods(http.MethodPost)
+	s.Handle("/reservations/{id}", httpkit.InstrumentingMiddleware(getReservationByIDHandler, metrics, "GetReservationByID")).Methods(http.MethodGet)
+	s.Handle("/reservations/{id}/confirm", httpkit.InstrumentingMiddleware(confirmReservationHandler, metrics, "ConfirmReservation")).Methods(http.MethodPost)
+	s.Handle("/reservations/{id}/release", httpkit.InstrumentingMiddleware(releaseReservationHandler, metrics, "ReleaseReservation")).Methods(http.MethodPost)
 	return r
 }
 
 func decodeListProductsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
-	query := r.URL.Query()
+	return NewListProductsRequest(r.URL.Query())
+}
+
+// NewListProductsRequest builds the ListProducts endpoint request from the listing query parameters,
+// so other transports share their syntax and validation.
+func NewListProductsRequest(query url.Values) (request interface{}, err error) {
 	pageSize, err := strconv.Atoi(query.Get("page_size"))
 	if err != nil || pageSize <= 0 {
 		pageSize = defaultPageSize
@@ -54,84 +167,455 @@ func decodeListProductsRequest(_ context.Context, r *http.Request) (request inte
 	if err != nil || pageNum <= 0 {
 		pageNum = 1
 	}
+	countTotal := true
+	if value := query.Get("with_total"); value != "" {
+		if countTotal, err = strconv.ParseBool(value); err != nil {
+			return nil, errors.Wrap(ErrBadRequest, "can't parse with_total value")
+		}
+	}
+	filters, err := decodeFilters(query)
+	if err != nil {
+		return nil, err
+	}
+	sort, err := parseListingSort(query, filters)
+	if err != nil {
+		return nil, err
+	}
 	pageSpec := &application.PageSpec{
-		Size:   pageSize,
-		Number: pageNum,
+		Size:       pageSize,
+		Number:     pageNum,
+		CountTotal: countTotal,
+		Sort:       sort,
 	}
 	result := &listProductsRequest{
 		PageSpec: pageSpec,
-		Filters: &application.Filters{
-			Color:    &[]string{},
-			Material: &[]string{},
-		},
+		After:    query.Get("after"),
+		Query:    query,
+		Filters:  filters,
+	}
+	return result, nil
+}
+
+func decodeBatchGetProductsQuery(_ context.Context, r *http.Request) (request interface{}, err error) {
+	query := r.URL.Query()
+	req := batchGetProductsRequest{}
+	if value := query.Get("ids"); value != "" {
+		req.IDStrs = strings.Split(value, valuesSeparator)
+	}
+	if value := query.Get("skus"); value != "" {
+		req.SKUs = strings.Split(value, valuesSeparator)
+	}
+	if err := validateBatchGetProductsRequest(&req); err != nil {
+		return nil, err
+	}
+	if req.Pricing, err = decodePriceSelection(query); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func decodeBatchGetProductsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req batchGetProductsRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil && e != io.EOF {
+		return nil, errors.Wrap(ErrBadRequest, e.Error())
+	}
+	if err := validateBatchGetProductsRequest(&req); err != nil {
+		return nil, err
+	}
+	if req.Pricing, err = decodePriceSelection(r.URL.Query()); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// NewBatchGetProductsRequest builds the BatchGetProducts endpoint request, prices are selected by the currency,
+// price_list and at parameters of the query.
+func NewBatchGetProductsRequest(ids []uuid.UUID, skus []string, query url.Values) (request interface{}, err error) {
+	req := batchGetProductsRequest{IDs: ids, SKUs: skus}
+	if err := validateBatchGetProductsRequest(&req); err != nil {
+		return nil, err
+	}
+	if req.Pricing, err = decodePriceSelection(query); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func validateBatchGetProductsRequest(req *batchGetProductsRequest) error {
+	count := len(req.IDStrs) + len(req.IDs) + len(req.SKUs)
+	if count == 0 {
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'ids' or 'skus'")
+	}
+	if count > maxBatchSize {
+		return errors.Wrapf(ErrBadRequest, "at most %d ids and SKUs are allowed", maxBatchSize)
+	}
+	for _, s := range req.IDStrs {
+		id, err := uuid.FromString(s)
+		if err != nil {
+			return errors.Wrapf(ErrBadRequest, "invalid product id '%s'", s)
+		}
+		req.IDs = append(req.IDs, id)
+	}
+	for _, sku := range req.SKUs {
+		if sku == "" {
+			return errors.Wrap(ErrBadRequest, "SKU must not be empty")
+		}
 	}
-	if err := parseFilter(query, "price", parseDecimalRangeFilter, &result.Filters.Price); err != nil {
+	return nil
+}
+
+func decodeProductFacetsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	query := r.URL.Query()
+	filters, err := decodeFilters(query)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := parseFacetSpec(query)
+	if err != nil {
+		return nil, errors.Wrap(ErrBadRequest, err.Error())
+	}
+	return &productFacetsRequest{Filters: filters, Spec: spec}, nil
+}
+
+func decodeFilters(query url.Values) (filters *application.Filters, err error) {
+	filters = &application.Filters{
+		Color:    &[]string{},
+		Material: &[]string{},
+		Search:   strings.TrimSpace(query.Get("q")),
+	}
+	if filters.Pricing, err = decodePriceSelection(query); err != nil {
+		return nil, err
+	}
+	if err := parseFilter(query, "price", parseDecimalRangeFilter, &filters.Price); err != nil {
 		return nil, errors.Wrap(ErrBadRequest, err.Error())
 	}
-	if err := parseFilter(query, "color", parseStringOrFilter, result.Filters.Color); err != nil {
+	if err := parseFilter(query, "color", parseStringOrFilter, filters.Color); err != nil {
 		return nil, errors.Wrap(ErrBadRequest, err.Error())
 	}
-	if err := parseFilter(query, "material", parseStringOrFilter, result.Filters.Material); err != nil {
+	if err := parseFilter(query, "material", parseStringOrFilter, filters.Material); err != nil {
 		return nil, errors.Wrap(ErrBadRequest, err.Error())
 	}
-	return result, nil
+	if filters.Category, err = parseCategoryFilter(query); err != nil {
+		return nil, errors.Wrap(ErrBadRequest, err.Error())
+	}
+	if filters.Warehouse, filters.InStock, err = parseWarehouseFilter(query); err != nil {
+		return nil, errors.Wrap(ErrBadRequest, err.Error())
+	}
+	if value := query.Get("filter"); value != "" {
+		if filters.Expr, err = application.ParseFilterExpr(value); err != nil {
+			return nil, errors.Wrap(ErrBadRequest, err.Error())
+		}
+	}
+	if value := query.Get("group_variants"); value != "" {
+		if filters.GroupVariants, err = strconv.ParseBool(value); err != nil {
+			return nil, errors.Wrap(ErrBadRequest, "can't parse group_variants value")
+		}
+	}
+	return filters, nil
+}
+
+// decodePriceSelection reads the 'currency' and 'price_list' parameters selecting the prices of products read
+// and the 'at' time prices are resolved at, the current time when omitted.
+func decodePriceSelection(query url.Values) (*application.PriceSelection, error) {
+	currency, list := query.Get("currency"), query.Get("price_list")
+	selection := &application.PriceSelection{}
+	at, err := parseOptionalTime(query, "at")
+	if err != nil {
+		return nil, err
+	}
+	if at != nil {
+		selection.At = *at
+	}
+	if currency != "" {
+		if selection.Currency, err = application.ParseCurrency(currency); err != nil {
+			return nil, err
+		}
+	}
+	if list != "" {
+		id, err := uuid.FromString(list)
+		if err != nil {
+			return nil, errors.Wrap(ErrBadRequest, "invalid parameter 'price_list'")
+		}
+		listID := application.PriceListID(id)
+		selection.PriceListID = &listID
+	}
+	return selection, nil
 }
 
 func decodeCreateProductRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
-	var req createProductRequest
-	if e := json.NewDecoder(r.Body).Decode(&req); e != nil && e != io.EOF {
+	return NewCreateProductRequest(r.Body, r.Header.Get(actorHeader))
+}
+
+// NewCreateProductRequest builds the CreateProduct endpoint request from the JSON document of the product.
+func NewCreateProductRequest(body io.Reader, actor string) (request interface{}, err error) {
+	req := createProductRequest{Actor: actor}
+	if e := json.NewDecoder(body).Decode(&req); e != nil && e != io.EOF {
 		return nil, e
 	}
+	if err := validateProductParams(&req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// parseListingSort reads the sort order of listings, searches are sorted by relevance by default.
+func parseListingSort(query url.Values, filters *application.Filters) (application.SortSpec, error) {
+	sort, err := parseSortSpec(query)
+	if err != nil {
+		return nil, errors.Wrap(ErrBadRequest, err.Error())
+	}
+	if filters.Search == "" && sort.Contains(application.SortByRelevance) {
+		return nil, errors.Wrap(ErrBadRequest, "sorting by relevance requires 'q' parameter")
+	}
+	if filters.Search != "" && len(sort) == 0 {
+		sort = application.SortSpec{{Field: application.SortByRelevance, Desc: true}}
+	}
+	return sort, nil
+}
+
+// decodeExportProductsRequest takes the filters and the sort order of ListProducts, the whole listing
+// is exported instead of a page.
+func decodeExportProductsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	if _, ok := exportFormats[format]; !ok {
+		return nil, errors.Wrapf(ErrBadRequest, "unsupported export format '%s'", format)
+	}
+	filters, err := decodeFilters(query)
+	if err != nil {
+		return nil, err
+	}
+	sort, err := parseListingSort(query, filters)
+	if err != nil {
+		return nil, err
+	}
+	return &exportProductsRequest{
+		Format:  format,
+		Filters: filters,
+		Sort:    sort,
+		Gzip:    acceptsGzip(r.Header.Get("Accept-Encoding")),
+	}, nil
+}
+
+// makeExportResponseEncoder streams the export page by page. Errors before the first page is written get
+// the usual error response, later errors can only abort the response, so they are logged here.
+func makeExportResponseEncoder(logger log.Logger) gokithttp.EncodeResponseFunc {
+	return func(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+		res := response.(*exportProductsResponse)
+		format := exportFormats[res.Format]
+		var out io.Writer = w
+		var compressor *gzip.Writer
+		var writer productWriter
+		start := func() error {
+			w.Header().Set("Content-Type", format.contentType)
+			w.Header().Set("Content-Disposition", `attachment; filename="products.`+format.extension+`"`)
+			w.Header().Set("Vary", "Accept-Encoding")
+			if res.Gzip {
+				w.Header().Set("Content-Encoding", "gzip")
+				compressor = gzip.NewWriter(w)
+				out = compressor
+			}
+			w.WriteHeader(http.StatusOK)
+			writer = format.newWriter(out, res.Feed)
+			return writer.begin()
+		}
+		err := res.Export(func(items []*application.Product) error {
+			if writer == nil {
+				if err := start(); err != nil {
+					return err
+				}
+			}
+			return writer.write(items)
+		})
+		if err != nil && writer == nil {
+			return err
+		}
+		if err == nil && writer == nil {
+			err = start()
+		}
+		if err == nil {
+			err = writer.end()
+		}
+		if err == nil && compressor != nil {
+			err = compressor.Close()
+		}
+		if err != nil {
+			_ = level.Error(logger).Log("msg", "product export interrupted", "err", err)
+			panic(http.ErrAbortHandler)
+		}
+		return nil
+	}
+}
+
+func decodeImportProductsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	format, err := importFormat(r)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := decodeImportRows(format, r.Body, maxImportRows)
+	if err != nil {
+		return nil, err
+	}
+	return &importProductsRequest{Rows: rows, Actor: r.Header.Get(actorHeader)}, nil
+}
+
+func decodeUpdateProductRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := parseIfMatch(r)
+	if err != nil {
+		return nil, err
+	}
+	req := updateProductRequest{ID: id, Versions: versions}
+	req.Actor = r.Header.Get(actorHeader)
+	if e := json.NewDecoder(r.Body).Decode(&req.createProductRequest); e != nil && e != io.EOF {
+		return nil, errors.Wrap(ErrBadRequest, e.Error())
+	}
+	if err := validateProductParams(&req.createProductRequest); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func decodePatchProductRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := parseIfMatch(r)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if e := json.NewDecoder(r.Body).Decode(&fields); e != nil {
+		return nil, errors.Wrap(ErrBadRequest, "request body must be a JSON merge patch object")
+	}
+	req := patchProductRequest{ID: id, Versions: versions, Actor: r.Header.Get(actorHeader)}
+	if req.Patch, err = parseProductPatch(fields); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func validateProductParams(req *createProductRequest) (err error) {
 	if req.Title == "" {
-		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'title'")
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'title'")
 	}
 	if req.SKU == "" {
-		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'sku'")
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'sku'")
 	}
 	if req.PriceStr == "" {
-		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'price'")
-	}
-	if req.AvailableQty == nil {
-		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'availableQty'")
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'price'")
 	}
 	if req.Image == nil {
-		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'image'")
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'image'")
 	}
 	if req.Image.URL == "" {
-		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'image.url'")
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'image.url'")
 	}
 	if req.Image.Width == nil {
-		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'image.width'")
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'image.width'")
 	}
 	if req.Image.Height == nil {
-		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'image.height'")
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'image.height'")
 	}
 	if req.Color == "" {
-		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'color'")
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'color'")
 	}
 	if req.Material == "" {
-		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'material'")
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'material'")
 	}
 
 	req.Price, err = decimal.NewFromString(req.PriceStr)
 	if err != nil {
-		return nil, errors.Wrap(ErrBadRequest, err.Error())
+		return errors.Wrap(ErrBadRequest, err.Error())
+	}
+	if req.Currency != "" {
+		if req.Currency, err = application.ParseCurrency(req.Currency); err != nil {
+			return err
+		}
+	}
+	if req.ParentIDStr != nil {
+		parentID, err := uuid.FromString(*req.ParentIDStr)
+		if err != nil {
+			return errors.Wrap(ErrBadRequest, "invalid parameter 'parent_id'")
+		}
+		req.ParentID = &parentID
 	}
 
-	return &req, nil
+	return nil
+}
+
+func decodePathIDRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
 }
 
 func decodeGetProductByIDRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	pricing, err := decodePriceSelection(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+	return &getProductByIDRequest{ID: id, Pricing: pricing, IfNoneMatch: r.Header.Get("If-None-Match")}, nil
+}
+
+func decodeGetProductBySKURequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	sku, ok := mux.Vars(r)["sku"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	pricing, err := decodePriceSelection(r.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+	return &getProductBySKURequest{SKU: sku, Pricing: pricing, IfNoneMatch: r.Header.Get("If-None-Match")}, nil
+}
+
+// NewGetProductByIDRequest builds the GetProductByID endpoint request, prices are selected by the currency,
+// price_list and at parameters of the query.
+func NewGetProductByIDRequest(id uuid.UUID, query url.Values) (request interface{}, err error) {
+	pricing, err := decodePriceSelection(query)
+	if err != nil {
+		return nil, err
+	}
+	return &getProductByIDRequest{ID: id, Pricing: pricing}, nil
+}
+
+func decodeDeleteProductRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := parseIfMatch(r)
+	if err != nil {
+		return nil, err
+	}
+	return &deleteProductRequest{ID: id, Versions: versions}, nil
+}
+
+func decodePathID(r *http.Request) (uuid.UUID, error) {
 	vars := mux.Vars(r)
 	sID, ok := vars["id"]
 	if !ok {
-		return nil, ErrBadRouting
+		return uuid.UUID{}, ErrBadRouting
 	}
 	id, err := uuid.FromString(sID)
 	if err != nil {
-		return nil, ErrBadRequest
+		return uuid.UUID{}, ErrBadRequest
 	}
-	return &id, nil
+	return id, nil
 }
 
 func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
@@ -139,7 +623,23 @@ func encodeResponse(ctx context.Context, w http.ResponseWriter, response interfa
 		w.WriteHeader(http.StatusNoContent)
 		return nil
 	}
+	if headerer, ok := response.(gokithttp.Headerer); ok {
+		for k, values := range headerer.Headers() {
+			for _, v := range values {
+				w.Header().Add(k, v)
+			}
+		}
+	}
+	code := http.StatusOK
+	if sc, ok := response.(gokithttp.StatusCoder); ok {
+		code = sc.StatusCode()
+	}
+	if code == http.StatusNotModified {
+		w.WriteHeader(code)
+		return nil
+	}
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
 	return json.NewEncoder(w).Encode(response)
 }
 
@@ -180,6 +680,206 @@ func translateError(err error) transportError {
 				Message: err.Error(),
 			},
 		}
+	} else if errors.Is(err, application.ErrVersionMismatch) {
+		return transportError{
+			Status: http.StatusPreconditionFailed,
+			Response: errorResponse{
+				Code:    104,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, ErrPreconditionRequired) {
+		return transportError{
+			Status: http.StatusPreconditionRequired,
+			Response: errorResponse{
+				Code:    105,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrInvalidVariant) {
+		return transportError{
+			Status: http.StatusBadRequest,
+			Response: errorResponse{
+				Code:    110,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrCategoryNotFound) {
+		return transportError{
+			Status: http.StatusNotFound,
+			Response: errorResponse{
+				Code:    106,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrDuplicateCategory) {
+		return transportError{
+			Status: http.StatusConflict,
+			Response: errorResponse{
+				Code:    107,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrInvalidParent) {
+		return transportError{
+			Status: http.StatusBadRequest,
+			Response: errorResponse{
+				Code:    108,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrCategoryHasChildren) {
+		return transportError{
+			Status: http.StatusConflict,
+			Response: errorResponse{
+				Code:    109,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrInsufficientStock) {
+		return transportError{
+			Status: http.StatusConflict,
+			Response: errorResponse{
+				Code:    111,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrReservationNotFound) {
+		return transportError{
+			Status: http.StatusNotFound,
+			Response: errorResponse{
+				Code:    112,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrReservationClosed) {
+		return transportError{
+			Status: http.StatusConflict,
+			Response: errorResponse{
+				Code:    113,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrInvalidStockAdjustment) {
+		return transportError{
+			Status: http.StatusBadRequest,
+			Response: errorResponse{
+				Code:    114,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrWarehouseNotFound) {
+		return transportError{
+			Status: http.StatusNotFound,
+			Response: errorResponse{
+				Code:    115,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrDuplicateWarehouse) {
+		return transportError{
+			Status: http.StatusConflict,
+			Response: errorResponse{
+				Code:    116,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrInvalidWebhook) {
+		return transportError{
+			Status: http.StatusBadRequest,
+			Response: errorResponse{
+				Code:    117,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrWebhookNotFound) {
+		return transportError{
+			Status: http.StatusNotFound,
+			Response: errorResponse{
+				Code:    118,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrDeadLetterNotFound) {
+		return transportError{
+			Status: http.StatusNotFound,
+			Response: errorResponse{
+				Code:    119,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrInvalidSKU) {
+		return transportError{
+			Status: http.StatusBadRequest,
+			Response: errorResponse{
+				Code:    120,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrImportJobNotFound) {
+		return transportError{
+			Status: http.StatusNotFound,
+			Response: errorResponse{
+				Code:    121,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrPriceListNotFound) {
+		return transportError{
+			Status: http.StatusNotFound,
+			Response: errorResponse{
+				Code:    122,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrDuplicatePriceList) {
+		return transportError{
+			Status: http.StatusConflict,
+			Response: errorResponse{
+				Code:    123,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrPriceNotFound) {
+		return transportError{
+			Status: http.StatusNotFound,
+			Response: errorResponse{
+				Code:    124,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrInvalidCurrency) {
+		return transportError{
+			Status: http.StatusBadRequest,
+			Response: errorResponse{
+				Code:    125,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrAvailableQtyChange) {
+		return transportError{
+			Status: http.StatusBadRequest,
+			Response: errorResponse{
+				Code:    127,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrAvailableQtyRequired) {
+		return transportError{
+			Status: http.StatusBadRequest,
+			Response: errorResponse{
+				Code:    128,
+				Message: err.Error(),
+			},
+		}
+	} else if errors.Is(err, application.ErrScheduledPriceNotFound) {
+		return transportError{
+			Status: http.StatusNotFound,
+			Response: errorResponse{
+				Code:    126,
+				Message: err.Error(),
+			},
+		}
 	} else {
 		return transportError{
 			Status: http.StatusInternalServerError,
@@ -0,0 +1,233 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+
+	// maxImportRows limits the number of products imported by one request
+	maxImportRows = 10000
+	// maxImportJobRows and maxImportJobSize limit the files of import jobs
+	maxImportJobRows = 1000000
+	maxImportJobSize = 64 << 20
+	// maxImportLineSize limits the size of one NDJSON document
+	maxImportLineSize = 1 << 20
+
+	// importListSeparator separates variant axes and options in a CSV cell
+	importListSeparator = "|"
+)
+
+// productCSVColumns are the columns of product CSV files. Exports write all of them, imports accept any subset
+// and ignore the id column since products are matched by SKU.
+var productCSVColumns = []string{
+	"id",
+	"title",
+	"sku",
+	"price",
+	"currency",
+	"available_qty",
+	"image_url",
+	"image_width",
+	"image_height",
+	"color",
+	"material",
+	"parent_id",
+	"variant_axes",
+	"options",
+}
+
+// DecodeImportRows reads products from the file of an import job.
+func DecodeImportRows(format string, body io.Reader) ([]application.ImportRow, error) {
+	return decodeImportRows(format, body, maxImportJobRows)
+}
+
+// importFormat takes the format of an import file from the 'format' parameter or from the content type.
+func importFormat(r *http.Request) (string, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format, nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return ImportFormatCSV, nil
+	case "application/x-ndjson", "application/jsonl":
+		return ImportFormatNDJSON, nil
+	default:
+		return "", errors.Wrap(ErrBadRequest, "content type must be text/csv or application/x-ndjson")
+	}
+}
+
+// decodeImportRows reads products from a CSV or NDJSON import file of at most maxRows rows. Every row is
+// validated with the rules of product creation, invalid rows are returned with the error so that the rest
+// of the file can be imported.
+func decodeImportRows(format string, body io.Reader, maxRows int) ([]application.ImportRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		return decodeCSVRows(body, maxRows)
+	case ImportFormatNDJSON:
+		return decodeNDJSONRows(body, maxRows)
+	default:
+		return nil, errors.Wrapf(ErrBadRequest, "unsupported import format '%s'", format)
+	}
+}
+
+// decodeCSVRows reads a CSV file with a header naming the product attributes. Image attributes are flattened
+// into image_url, image_width and image_height, variant axes are separated by '|' and options are written
+// as axis=value pairs separated by '|'.
+func decodeCSVRows(body io.Reader, maxRows int) ([]application.ImportRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.Wrap(ErrBadRequest, "import file is empty")
+	}
+	if err != nil {
+		return nil, errors.Wrap(ErrBadRequest, err.Error())
+	}
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+		if !isProductCSVColumn(header[i]) {
+			return nil, errors.Wrapf(ErrBadRequest, "unknown column '%s'", column)
+		}
+	}
+
+	var rows []application.ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(ErrBadRequest, err.Error())
+		}
+		if len(rows) == maxRows {
+			return nil, errors.Wrapf(ErrBadRequest, "import file is limited to %d rows", maxRows)
+		}
+		row := application.ImportRow{Number: len(rows) + 1}
+		if len(record) != len(header) {
+			row.Err = errors.Wrapf(ErrBadRequest, "expected %d fields, got %d", len(header), len(record))
+		} else {
+			values := make(map[string]string, len(header))
+			for i, column := range header {
+				values[column] = strings.TrimSpace(record[i])
+			}
+			row.SKU = values["sku"]
+			row.Params, row.Err = parseCSVProduct(values)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseCSVProduct(values map[string]string) (application.ProductParams, error) {
+	req := createProductRequest{
+		Title:    values["title"],
+		SKU:      values["sku"],
+		PriceStr: values["price"],
+		Currency: values["currency"],
+		Color:    values["color"],
+		Material: values["material"],
+	}
+	var err error
+	if req.AvailableQty, err = parseCSVInt(values, "available_qty"); err != nil {
+		return nil, err
+	}
+	if values["image_url"] != "" || values["image_width"] != "" || values["image_height"] != "" {
+		req.Image = &image{URL: values["image_url"]}
+		if req.Image.Width, err = parseCSVInt(values, "image_width"); err != nil {
+			return nil, err
+		}
+		if req.Image.Height, err = parseCSVInt(values, "image_height"); err != nil {
+			return nil, err
+		}
+	}
+	if parentID := values["parent_id"]; parentID != "" {
+		req.ParentIDStr = &parentID
+	}
+	if axes := values["variant_axes"]; axes != "" {
+		req.VariantAxes = strings.Split(axes, importListSeparator)
+	}
+	if options := values["options"]; options != "" {
+		req.Options = make(map[string]string)
+		for _, option := range strings.Split(options, importListSeparator) {
+			pair := strings.SplitN(option, "=", 2)
+			if len(pair) != 2 || pair[0] == "" {
+				return nil, errors.Wrap(ErrBadRequest, "options must be axis=value pairs separated by '|'")
+			}
+			req.Options[pair[0]] = pair[1]
+		}
+	}
+	if err = validateProductParams(&req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func isProductCSVColumn(name string) bool {
+	for _, column := range productCSVColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCSVInt(values map[string]string, column string) (*int, error) {
+	value := values[column]
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, errors.Wrapf(ErrBadRequest, "invalid parameter '%s'", column)
+	}
+	return &n, nil
+}
+
+// decodeNDJSONRows reads one JSON document of the product creation request per line, blank lines are skipped.
+func decodeNDJSONRows(body io.Reader, maxRows int) ([]application.ImportRow, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), maxImportLineSize)
+
+	var rows []application.ImportRow
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, errors.Wrapf(ErrBadRequest, "import file is limited to %d rows", maxRows)
+		}
+		row := application.ImportRow{Number: len(rows) + 1}
+		req := createProductRequest{}
+		if err := json.Unmarshal(line, &req); err != nil {
+			row.Err = errors.Wrap(ErrBadRequest, err.Error())
+			rows = append(rows, row)
+			continue
+		}
+		row.SKU = req.SKU
+		if row.Err = validateProductParams(&req); row.Err == nil {
+			row.Params = &req
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(ErrBadRequest, err.Error())
+	}
+	return rows, nil
+}
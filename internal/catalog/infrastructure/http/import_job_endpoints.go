@@ -0,0 +1,60 @@
+package http
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/jnikolaeva/eshop-common/uuid"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+func makeCreateImportJobEndpoint(s application.ImportJobService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*createImportJobRequest)
+		job, err := s.Create(req.Format, req.Content, req.TotalRows, req.Actor)
+		if err != nil {
+			return nil, err
+		}
+		return &createImportJobResponse{*toImportJob(job)}, nil
+	}
+}
+
+func makeGetImportJobByIDEndpoint(s application.ImportJobService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(*uuid.UUID)
+		job, err := s.FindByID(*id)
+		if err != nil {
+			return nil, err
+		}
+		return &getImportJobResponse{*toImportJob(job)}, nil
+	}
+}
+
+func makeGetImportJobErrorsEndpoint(s application.ImportJobService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(*uuid.UUID)
+		rows, err := s.FindErrors(*id)
+		if err != nil {
+			return nil, err
+		}
+		return &importJobErrorsResponse{JobID: *id, Rows: rows}, nil
+	}
+}
+
+func toImportJob(job *application.ImportJob) *importJob {
+	return &importJob{
+		ID:            job.ID.String(),
+		Format:        job.Format,
+		Status:        string(job.Status),
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		Created:       job.Created,
+		Updated:       job.Updated,
+		Failed:        job.Failed,
+		Error:         job.Error,
+		CreatedAt:     job.CreatedAt,
+		UpdatedAt:     job.UpdatedAt,
+		FinishedAt:    job.FinishedAt,
+	}
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// decodeCreateImportJobRequest reads the whole file to store it with the job. The file is decoded up front
+// to reject unreadable files and to count the rows.
+func decodeCreateImportJobRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	format, err := importFormat(r)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadAll(io.LimitReader(r.Body, maxImportJobSize+1))
+	if err != nil {
+		return nil, errors.Wrap(ErrBadRequest, err.Error())
+	}
+	if len(content) > maxImportJobSize {
+		return nil, errors.Wrapf(ErrBadRequest, "import file is limited to %d MB", maxImportJobSize>>20)
+	}
+	rows, err := decodeImportRows(format, bytes.NewReader(content), maxImportJobRows)
+	if err != nil {
+		return nil, err
+	}
+	return &createImportJobRequest{
+		Format:    format,
+		Content:   content,
+		TotalRows: len(rows),
+		Actor:     r.Header.Get(actorHeader),
+	}, nil
+}
+
+// encodeImportJobErrorsResponse writes the failed rows of the job as a CSV attachment.
+func encodeImportJobErrorsResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(*importJobErrorsResponse)
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%s-errors.csv"`, res.JobID))
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"row", "sku", "error"}); err != nil {
+		return err
+	}
+	for _, row := range res.Rows {
+		if err := writer.Write([]string{strconv.Itoa(row.Number), row.SKU, row.Err.Error()}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
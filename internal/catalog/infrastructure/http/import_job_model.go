@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+type importJob struct {
+	ID            string     `json:"id"`
+	Format        string     `json:"format"`
+	Status        string     `json:"status"`
+	TotalRows     int        `json:"total_rows"`
+	ProcessedRows int        `json:"processed_rows"`
+	Created       int        `json:"created"`
+	Updated       int        `json:"updated"`
+	Failed        int        `json:"failed"`
+	Error         string     `json:"error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+type createImportJobRequest struct {
+	Format    string
+	Content   []byte
+	TotalRows int
+	Actor     string
+}
+
+type createImportJobResponse struct {
+	importJob
+}
+
+func (r *createImportJobResponse) StatusCode() int {
+	return http.StatusAccepted
+}
+
+type getImportJobResponse struct {
+	importJob
+}
+
+type importJobErrorsResponse struct {
+	JobID uuid.UUID
+	Rows  []application.ImportRowResult
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+// pageLinks builds RFC 8288 links relative to the listing request, keeping its filters.
+// Keyset pages link only forward, while numbered pages also get prev and last links.
+func pageLinks(query url.Values, spec *application.PageSpec, page *application.ProductsPage, after string) []string {
+	keyset := spec.After != nil
+	links := []string{formatLink(withPage(query, spec.Size, 1), "first")}
+	if page.HasNext {
+		if keyset {
+			links = append(links, formatLink(withCursor(query, spec.Size, after), "next"))
+		} else {
+			links = append(links, formatLink(withPage(query, spec.Size, spec.Number+1), "next"))
+		}
+	}
+	if keyset {
+		return links
+	}
+	if spec.Number > 1 {
+		links = append(links, formatLink(withPage(query, spec.Size, spec.Number-1), "prev"))
+	}
+	if page.Total != nil {
+		last := (*page.Total + spec.Size - 1) / spec.Size
+		if last < 1 {
+			last = 1
+		}
+		links = append(links, formatLink(withPage(query, spec.Size, last), "last"))
+	}
+	return links
+}
+
+func withPage(query url.Values, size, number int) url.Values {
+	result := copyValues(query)
+	result.Del("after")
+	result.Set("page_size", strconv.Itoa(size))
+	result.Set("page_num", strconv.Itoa(number))
+	return result
+}
+
+func withCursor(query url.Values, size int, after string) url.Values {
+	result := copyValues(query)
+	result.Del("page_num")
+	result.Set("page_size", strconv.Itoa(size))
+	result.Set("after", after)
+	return result
+}
+
+func copyValues(values url.Values) url.Values {
+	result := make(url.Values, len(values))
+	for k, v := range values {
+		result[k] = append([]string(nil), v...)
+	}
+	return result
+}
+
+func formatLink(query url.Values, rel string) string {
+	return fmt.Sprintf(`<?%s>; rel="%s"`, query.Encode(), rel)
+}
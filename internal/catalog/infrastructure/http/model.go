@@ -1,6 +1,11 @@
 package http
 
 import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
 	"github.com/shopspring/decimal"
 
 	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
@@ -8,24 +13,77 @@ import (
 
 type listProductsRequest struct {
 	PageSpec *application.PageSpec
+	After    string
 	Filters  *application.Filters
+	Query    url.Values
 }
 
 type listProductsResponse struct {
-	Items []*product `json:"items"`
-	After string     `json:"after,omitempty"`
-	Count int        `json:"count"`
+	Items   []*product `json:"items"`
+	After   string     `json:"after,omitempty"`
+	Count   int        `json:"count"`
+	Total   *int       `json:"total,omitempty"`
+	HasNext bool       `json:"has_next"`
+	HasPrev bool       `json:"has_prev"`
+	links   []string
+}
+
+func (r *listProductsResponse) Headers() http.Header {
+	return http.Header{"Link": []string{strings.Join(r.links, ", ")}}
+}
+
+type productFacetsRequest struct {
+	Filters *application.Filters
+	Spec    application.FacetSpec
+}
+
+type productFacetsResponse struct {
+	Color    []facetValue  `json:"color,omitempty"`
+	Material []facetValue  `json:"material,omitempty"`
+	Price    []priceBucket `json:"price,omitempty"`
+}
+
+type facetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type priceBucket struct {
+	From  *decimal.Decimal `json:"from"`
+	To    *decimal.Decimal `json:"to"`
+	Count int              `json:"count"`
 }
 
 type product struct {
-	ID           string          `json:"id"`
-	Title        string          `json:"title"`
-	SKU          string          `json:"sku"`
-	Price        decimal.Decimal `json:"price"`
-	AvailableQty int             `json:"available_qty"`
-	Image        image           `json:"image"`
-	Color        string          `json:"color"`
-	Material     string          `json:"material"`
+	ID    string          `json:"id"`
+	Title string          `json:"title"`
+	SKU   string          `json:"sku"`
+	Price decimal.Decimal `json:"price"`
+	// CompareAtPrice is the regular price while a sale price is in effect
+	CompareAtPrice *decimal.Decimal  `json:"compare_at_price,omitempty"`
+	Currency       string            `json:"currency"`
+	AvailableQty   int               `json:"available_qty"`
+	Stock          []warehouseStock  `json:"stock,omitempty"`
+	Image          image             `json:"image"`
+	Color          string            `json:"color"`
+	Material       string            `json:"material"`
+	ParentID       *string           `json:"parent_id,omitempty"`
+	VariantAxes    []string          `json:"variant_axes,omitempty"`
+	Options        map[string]string `json:"options,omitempty"`
+	Variants       []*product        `json:"variants,omitempty"`
+	PriceRange     *priceRange       `json:"price_range,omitempty"`
+	Relevance      float64           `json:"relevance,omitempty"`
+	Highlight      string            `json:"highlight,omitempty"`
+}
+
+type warehouseStock struct {
+	WarehouseID  string `json:"warehouse_id"`
+	AvailableQty int    `json:"available_qty"`
+}
+
+type priceRange struct {
+	Min decimal.Decimal `json:"min"`
+	Max decimal.Decimal `json:"max"`
 }
 
 type image struct {
@@ -38,11 +96,17 @@ type createProductRequest struct {
 	Title        string `json:"title"`
 	SKU          string `json:"sku"`
 	PriceStr     string `json:"price"`
+	Currency     string `json:"currency"`
 	AvailableQty *int   `json:"available_qty"`
 	Price        decimal.Decimal
-	Image        *image `json:"image"`
-	Color        string `json:"color"`
-	Material     string `json:"material"`
+	Image        *image  `json:"image"`
+	Color        string  `json:"color"`
+	Material     string  `json:"material"`
+	ParentIDStr  *string `json:"parent_id"`
+	ParentID     *uuid.UUID
+	VariantAxes  []string          `json:"variant_axes"`
+	Options      map[string]string `json:"options"`
+	Actor        string            `json:"-"`
 }
 
 func (c *createProductRequest) GetTitle() string {
@@ -57,8 +121,12 @@ func (c *createProductRequest) GetPrice() decimal.Decimal {
 	return c.Price
 }
 
-func (c *createProductRequest) GetAvailableQty() int {
-	return *c.AvailableQty
+func (c *createProductRequest) GetCurrency() string {
+	return c.Currency
+}
+
+func (c *createProductRequest) GetAvailableQty() *int {
+	return c.AvailableQty
 }
 
 func (c *createProductRequest) GetImageURL() string {
@@ -81,12 +149,129 @@ func (c *createProductRequest) GetMaterial() string {
 	return c.Material
 }
 
+func (c *createProductRequest) GetParentID() *uuid.UUID {
+	return c.ParentID
+}
+
+func (c *createProductRequest) GetVariantAxes() []string {
+	return c.VariantAxes
+}
+
+func (c *createProductRequest) GetOptions() map[string]string {
+	return c.Options
+}
+
 type createProductResponse struct {
 	ID string `json:"id"`
 }
 
+type getProductBySKURequest struct {
+	SKU         string
+	Pricing     *application.PriceSelection
+	IfNoneMatch string
+}
+
+type batchGetProductsRequest struct {
+	IDStrs []string    `json:"ids"`
+	IDs    []uuid.UUID `json:"-"`
+	SKUs   []string    `json:"skus"`
+	// Pricing is read from the query parameters of both GET and POST requests
+	Pricing *application.PriceSelection `json:"-"`
+}
+
+type batchGetProductsResponse struct {
+	Items       []*product `json:"items"`
+	MissingIDs  []string   `json:"missing_ids"`
+	MissingSKUs []string   `json:"missing_skus"`
+}
+
+type exportProductsRequest struct {
+	Format  string
+	Filters *application.Filters
+	Sort    application.SortSpec
+	Gzip    bool
+}
+
+type exportProductsResponse struct {
+	Format string
+	Gzip   bool
+	Feed   MerchantFeed
+	// Export passes the exported products to write page by page
+	Export func(write func(items []*application.Product) error) error
+}
+
+type importProductsRequest struct {
+	Rows  []application.ImportRow
+	Actor string
+}
+
+type importProductsResponse struct {
+	Created int                `json:"created"`
+	Updated int                `json:"updated"`
+	Failed  int                `json:"failed"`
+	Rows    []*importRowResult `json:"rows"`
+}
+
+type importRowResult struct {
+	Row    int            `json:"row"`
+	SKU    string         `json:"sku,omitempty"`
+	ID     string         `json:"id,omitempty"`
+	Status string         `json:"status"`
+	Error  *errorResponse `json:"error,omitempty"`
+}
+
+type getProductByIDRequest struct {
+	ID          uuid.UUID
+	Pricing     *application.PriceSelection
+	IfNoneMatch string
+}
+
 type getProductByIDResponse struct {
 	product
+	etag string
+}
+
+func (r *getProductByIDResponse) Headers() http.Header {
+	return http.Header{"ETag": []string{r.etag}}
+}
+
+type notModifiedResponse struct {
+	etag string
+}
+
+func (r *notModifiedResponse) Headers() http.Header {
+	return http.Header{"ETag": []string{r.etag}}
+}
+
+func (r *notModifiedResponse) StatusCode() int {
+	return http.StatusNotModified
+}
+
+type updateProductRequest struct {
+	ID       uuid.UUID
+	Versions []int
+	createProductRequest
+}
+
+type patchProductRequest struct {
+	ID       uuid.UUID
+	Versions []int
+	Patch    application.ProductPatch
+	Actor    string
+}
+
+type updateProductResponse struct {
+	product
+	etag string
+}
+
+func (r *updateProductResponse) Headers() http.Header {
+	return http.Header{"ETag": []string{r.etag}}
+}
+
+type deleteProductRequest struct {
+	ID       uuid.UUID
+	Versions []int
 }
 
 type errorResponse struct {
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+var jsonNull = []byte("null")
+
+func parseProductPatch(fields map[string]json.RawMessage) (patch application.ProductPatch, err error) {
+	if patch.Title, err = parseStringPatch(fields, "title", "title"); err != nil {
+		return patch, err
+	}
+	if patch.SKU, err = parseStringPatch(fields, "sku", "sku"); err != nil {
+		return patch, err
+	}
+	priceStr, err := parseStringPatch(fields, "price", "price")
+	if err != nil {
+		return patch, err
+	}
+	if priceStr != nil {
+		price, err := decimal.NewFromString(*priceStr)
+		if err != nil {
+			return patch, errors.Wrap(ErrBadRequest, err.Error())
+		}
+		patch.Price = &price
+	}
+	if patch.Currency, err = parseStringPatch(fields, "currency", "currency"); err != nil {
+		return patch, err
+	}
+	if patch.Currency != nil {
+		currency, err := application.ParseCurrency(*patch.Currency)
+		if err != nil {
+			return patch, err
+		}
+		patch.Currency = &currency
+	}
+	if patch.AvailableQty, err = parseIntPatch(fields, "available_qty", "available_qty"); err != nil {
+		return patch, err
+	}
+	if patch.Color, err = parseStringPatch(fields, "color", "color"); err != nil {
+		return patch, err
+	}
+	if patch.Material, err = parseStringPatch(fields, "material", "material"); err != nil {
+		return patch, err
+	}
+	var variantAxes []string
+	if ok, err := parseFieldPatch(fields, "variant_axes", "variant_axes", &variantAxes); err != nil {
+		return patch, err
+	} else if ok {
+		patch.VariantAxes = &variantAxes
+	}
+	var options map[string]string
+	if ok, err := parseFieldPatch(fields, "options", "options", &options); err != nil {
+		return patch, err
+	} else if ok {
+		patch.Options = &options
+	}
+
+	raw, ok := fields["image"]
+	if !ok {
+		return patch, nil
+	}
+	var imageFields map[string]json.RawMessage
+	if bytes.Equal(bytes.TrimSpace(raw), jsonNull) || json.Unmarshal(raw, &imageFields) != nil {
+		return patch, errors.Wrap(ErrBadRequest, "invalid value for parameter 'image'")
+	}
+	if patch.ImageURL, err = parseStringPatch(imageFields, "url", "image.url"); err != nil {
+		return patch, err
+	}
+	if patch.ImageWidth, err = parseIntPatch(imageFields, "width", "image.width"); err != nil {
+		return patch, err
+	}
+	if patch.ImageHeight, err = parseIntPatch(imageFields, "height", "image.height"); err != nil {
+		return patch, err
+	}
+	return patch, nil
+}
+
+func parseStringPatch(fields map[string]json.RawMessage, key, paramName string) (*string, error) {
+	var value string
+	ok, err := parseFieldPatch(fields, key, paramName, &value)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if value == "" {
+		return nil, errors.Wrapf(ErrBadRequest, "empty value for parameter '%s'", paramName)
+	}
+	return &value, nil
+}
+
+func parseIntPatch(fields map[string]json.RawMessage, key, paramName string) (*int, error) {
+	var value int
+	ok, err := parseFieldPatch(fields, key, paramName, &value)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func parseFieldPatch(fields map[string]json.RawMessage, key, paramName string, value interface{}) (bool, error) {
+	raw, ok := fields[key]
+	if !ok {
+		return false, nil
+	}
+	if bytes.Equal(bytes.TrimSpace(raw), jsonNull) {
+		return false, errors.Wrapf(ErrBadRequest, "required parameter '%s' can't be removed", paramName)
+	}
+	if err := json.Unmarshal(raw, value); err != nil {
+		return false, errors.Wrapf(ErrBadRequest, "invalid value for parameter '%s'", paramName)
+	}
+	return true, nil
+}
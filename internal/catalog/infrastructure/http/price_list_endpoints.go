@@ -0,0 +1,186 @@
+package http
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/jnikolaeva/eshop-common/uuid"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+func makeListPriceListsEndpoint(s application.PriceListService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		items, err := s.FindAll()
+		if err != nil {
+			return nil, err
+		}
+		res := &listPriceListsResponse{Items: make([]*priceList, len(items))}
+		for i, item := range items {
+			res.Items[i] = toPriceList(item)
+		}
+		return res, nil
+	}
+}
+
+func makeGetPriceListByIDEndpoint(s application.PriceListService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(*uuid.UUID)
+		item, err := s.FindByID(*id)
+		if err != nil {
+			return nil, err
+		}
+		return &getPriceListByIDResponse{*toPriceList(item)}, nil
+	}
+}
+
+func makeCreatePriceListEndpoint(s application.PriceListService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*createPriceListRequest)
+		id, err := s.Create(req)
+		if err != nil {
+			return nil, err
+		}
+		return &createPriceListResponse{ID: id.String()}, nil
+	}
+}
+
+func makeDeletePriceListEndpoint(s application.PriceListService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*deletePriceListRequest)
+		if err := s.Delete(req.ID, req.Actor); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+}
+
+func makeSetPriceEndpoint(s application.PriceListService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*setPriceRequest)
+		if err := s.SetPrice(req.ID, req.ProductID, req.Price, req.Actor); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+}
+
+func makeDeletePriceEndpoint(s application.PriceListService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*priceListEntryRequest)
+		if err := s.DeletePrice(req.ID, req.ProductID, req.Actor); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+}
+
+func makeListScheduledPricesEndpoint(s application.PriceListService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		productID := request.(*uuid.UUID)
+		items, err := s.FindScheduledPrices(*productID)
+		if err != nil {
+			return nil, err
+		}
+		res := &listScheduledPricesResponse{Items: make([]*scheduledPrice, len(items))}
+		for i, item := range items {
+			res.Items[i] = toScheduledPrice(item)
+		}
+		return res, nil
+	}
+}
+
+func makeSchedulePriceEndpoint(s application.PriceListService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*schedulePriceRequest)
+		id, err := s.SchedulePrice(req.ProductID, req, req.Actor)
+		if err != nil {
+			return nil, err
+		}
+		return &schedulePriceResponse{ID: id.String()}, nil
+	}
+}
+
+func makeDeleteScheduledPriceEndpoint(s application.PriceListService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*scheduledPriceRequest)
+		if err := s.DeleteScheduledPrice(req.ProductID, req.ID, req.Actor); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+}
+
+func makeGetPriceHistoryEndpoint(s application.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*priceHistoryRequest)
+		var page *application.PriceChangesPage
+		var err error
+		if req.ProductID != nil {
+			page, err = s.PriceHistory(*req.ProductID, req.Filter, req.PageSpec)
+		} else {
+			page, err = s.PriceHistoryBySKU(req.SKU, req.Filter, req.PageSpec)
+		}
+		if err != nil {
+			return nil, err
+		}
+		res := &priceHistoryResponse{
+			Items:   make([]*priceChange, len(page.Items)),
+			HasNext: page.HasNext,
+			HasPrev: page.HasPrev,
+		}
+		for i, item := range page.Items {
+			res.Items[i] = toPriceChange(item)
+		}
+		return res, nil
+	}
+}
+
+func toPriceChange(item *application.PriceChange) *priceChange {
+	result := &priceChange{
+		ID:        item.ID,
+		ProductID: item.ProductID.String(),
+		SKU:       item.SKU,
+		Reason:    string(item.Reason),
+		Currency:  item.Currency,
+		OldPrice:  item.OldPrice,
+		NewPrice:  item.NewPrice,
+		StartsAt:  item.StartsAt,
+		EndsAt:    item.EndsAt,
+		Actor:     item.Actor,
+		ChangedAt: item.ChangedAt.UTC(),
+	}
+	if item.PriceListID != nil {
+		id := item.PriceListID.String()
+		result.PriceListID = &id
+	}
+	if item.ScheduledPriceID != nil {
+		id := item.ScheduledPriceID.String()
+		result.ScheduledPriceID = &id
+	}
+	return result
+}
+
+func toScheduledPrice(item *application.ScheduledPrice) *scheduledPrice {
+	result := &scheduledPrice{
+		ID:       item.ID.String(),
+		Price:    item.Price,
+		StartsAt: item.StartsAt,
+		EndsAt:   item.EndsAt,
+	}
+	if item.PriceListID != nil {
+		id := item.PriceListID.String()
+		result.PriceListID = &id
+	}
+	return result
+}
+
+func toPriceList(item *application.PriceList) *priceList {
+	return &priceList{
+		ID:            item.ID.String(),
+		Name:          item.Name,
+		Currency:      item.Currency,
+		Market:        item.Market,
+		CustomerGroup: item.CustomerGroup,
+	}
+}
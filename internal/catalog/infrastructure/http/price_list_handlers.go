@@ -0,0 +1,169 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+func decodeCreatePriceListRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req createPriceListRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil && e != io.EOF {
+		return nil, errors.Wrap(ErrBadRequest, e.Error())
+	}
+	if req.Name == "" {
+		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'name'")
+	}
+	if req.Currency == "" {
+		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'currency'")
+	}
+	if req.Currency, err = application.ParseCurrency(req.Currency); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func decodeDeletePriceListRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return &deletePriceListRequest{ID: id, Actor: r.Header.Get(actorHeader)}, nil
+}
+
+func decodePriceListEntryRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	return decodePriceListEntry(r)
+}
+
+func decodeSetPriceRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	entry, err := decodePriceListEntry(r)
+	if err != nil {
+		return nil, err
+	}
+	req := setPriceRequest{priceListEntryRequest: *entry}
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil && e != io.EOF {
+		return nil, errors.Wrap(ErrBadRequest, e.Error())
+	}
+	if req.PriceStr == "" {
+		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'price'")
+	}
+	if req.Price, err = decimal.NewFromString(req.PriceStr); err != nil {
+		return nil, errors.Wrap(ErrBadRequest, err.Error())
+	}
+	if req.Price.IsNegative() {
+		return nil, errors.Wrap(ErrBadRequest, "price can't be negative")
+	}
+	return &req, nil
+}
+
+func decodePriceListEntry(r *http.Request) (*priceListEntryRequest, error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	productID, err := uuid.FromString(mux.Vars(r)["product_id"])
+	if err != nil {
+		return nil, errors.Wrap(ErrBadRequest, "invalid parameter 'product_id'")
+	}
+	return &priceListEntryRequest{ID: id, ProductID: productID, Actor: r.Header.Get(actorHeader)}, nil
+}
+
+func decodeSchedulePriceRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	productID, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	req := schedulePriceRequest{ProductID: productID, Actor: r.Header.Get(actorHeader)}
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil && e != io.EOF {
+		return nil, errors.Wrap(ErrBadRequest, e.Error())
+	}
+	if req.PriceStr == "" {
+		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'price'")
+	}
+	if req.Price, err = decimal.NewFromString(req.PriceStr); err != nil {
+		return nil, errors.Wrap(ErrBadRequest, err.Error())
+	}
+	if req.Price.IsNegative() {
+		return nil, errors.Wrap(ErrBadRequest, "price can't be negative")
+	}
+	if req.StartsAt == nil {
+		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'starts_at'")
+	}
+	if req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return nil, errors.Wrap(ErrBadRequest, "'ends_at' must be after 'starts_at'")
+	}
+	if req.PriceListIDStr != nil {
+		id, err := uuid.FromString(*req.PriceListIDStr)
+		if err != nil {
+			return nil, errors.Wrap(ErrBadRequest, "invalid parameter 'price_list_id'")
+		}
+		req.PriceListID = &id
+	}
+	return &req, nil
+}
+
+func decodeScheduledPriceRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	productID, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.FromString(mux.Vars(r)["price_id"])
+	if err != nil {
+		return nil, errors.Wrap(ErrBadRequest, "invalid parameter 'price_id'")
+	}
+	return &scheduledPriceRequest{ProductID: productID, ID: id, Actor: r.Header.Get(actorHeader)}, nil
+}
+
+func decodePriceHistoryRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return decodePriceHistoryFilter(r.URL.Query(), priceHistoryRequest{ProductID: &id})
+}
+
+func decodePriceHistoryBySKURequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	query := r.URL.Query()
+	sku := query.Get("sku")
+	if sku == "" {
+		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'sku'")
+	}
+	return decodePriceHistoryFilter(query, priceHistoryRequest{SKU: sku})
+}
+
+func decodePriceHistoryFilter(query url.Values, req priceHistoryRequest) (*priceHistoryRequest, error) {
+	var err error
+	req.PageSpec = parsePageNumber(query)
+	if req.Filter.From, err = parseOptionalTime(query, "from"); err != nil {
+		return nil, err
+	}
+	if req.Filter.To, err = parseOptionalTime(query, "to"); err != nil {
+		return nil, err
+	}
+	if req.Filter.From != nil && req.Filter.To != nil && !req.Filter.From.Before(*req.Filter.To) {
+		return nil, errors.Wrap(ErrBadRequest, "'from' must be before 'to'")
+	}
+	return &req, nil
+}
+
+func parseOptionalTime(query url.Values, name string) (*time.Time, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.Wrapf(ErrBadRequest, "invalid parameter '%s', RFC 3339 time expected", name)
+	}
+	return &t, nil
+}
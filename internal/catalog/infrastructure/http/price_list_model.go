@@ -0,0 +1,151 @@
+package http
+
+import (
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+type priceList struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Currency      string `json:"currency"`
+	Market        string `json:"market"`
+	CustomerGroup string `json:"customer_group"`
+}
+
+type listPriceListsResponse struct {
+	Items []*priceList `json:"items"`
+}
+
+type createPriceListRequest struct {
+	Name          string `json:"name"`
+	Currency      string `json:"currency"`
+	Market        string `json:"market"`
+	CustomerGroup string `json:"customer_group"`
+}
+
+func (c *createPriceListRequest) GetName() string {
+	return c.Name
+}
+
+func (c *createPriceListRequest) GetCurrency() string {
+	return c.Currency
+}
+
+func (c *createPriceListRequest) GetMarket() string {
+	return c.Market
+}
+
+func (c *createPriceListRequest) GetCustomerGroup() string {
+	return c.CustomerGroup
+}
+
+type createPriceListResponse struct {
+	ID string `json:"id"`
+}
+
+type deletePriceListRequest struct {
+	ID    uuid.UUID
+	Actor string
+}
+
+type getPriceListByIDResponse struct {
+	priceList
+}
+
+// priceListEntryRequest addresses the price of a product in a price list
+type priceListEntryRequest struct {
+	ID        uuid.UUID
+	ProductID uuid.UUID
+	Actor     string
+}
+
+type setPriceRequest struct {
+	priceListEntryRequest
+	PriceStr string `json:"price"`
+	Price    decimal.Decimal
+}
+
+type scheduledPrice struct {
+	ID          string          `json:"id"`
+	PriceListID *string         `json:"price_list_id,omitempty"`
+	Price       decimal.Decimal `json:"price"`
+	StartsAt    time.Time       `json:"starts_at"`
+	EndsAt      *time.Time      `json:"ends_at,omitempty"`
+}
+
+type listScheduledPricesResponse struct {
+	Items []*scheduledPrice `json:"items"`
+}
+
+type schedulePriceRequest struct {
+	ProductID      uuid.UUID       `json:"-"`
+	Actor          string          `json:"-"`
+	PriceListIDStr *string         `json:"price_list_id"`
+	PriceListID    *uuid.UUID      `json:"-"`
+	PriceStr       string          `json:"price"`
+	Price          decimal.Decimal `json:"-"`
+	StartsAt       *time.Time      `json:"starts_at"`
+	EndsAt         *time.Time      `json:"ends_at"`
+}
+
+func (c *schedulePriceRequest) GetPriceListID() *uuid.UUID {
+	return c.PriceListID
+}
+
+func (c *schedulePriceRequest) GetPrice() decimal.Decimal {
+	return c.Price
+}
+
+func (c *schedulePriceRequest) GetStartsAt() time.Time {
+	return *c.StartsAt
+}
+
+func (c *schedulePriceRequest) GetEndsAt() *time.Time {
+	return c.EndsAt
+}
+
+type schedulePriceResponse struct {
+	ID string `json:"id"`
+}
+
+// scheduledPriceRequest addresses a scheduled price of a product
+type scheduledPriceRequest struct {
+	ProductID uuid.UUID
+	ID        uuid.UUID
+	Actor     string
+}
+
+type priceChange struct {
+	ID               int64            `json:"id"`
+	ProductID        string           `json:"product_id"`
+	SKU              string           `json:"sku"`
+	PriceListID      *string          `json:"price_list_id,omitempty"`
+	Reason           string           `json:"reason"`
+	Currency         string           `json:"currency"`
+	OldPrice         *decimal.Decimal `json:"old_price"`
+	NewPrice         *decimal.Decimal `json:"new_price"`
+	ScheduledPriceID *string          `json:"scheduled_price_id,omitempty"`
+	StartsAt         *time.Time       `json:"starts_at,omitempty"`
+	EndsAt           *time.Time       `json:"ends_at,omitempty"`
+	Actor            string           `json:"actor,omitempty"`
+	ChangedAt        time.Time        `json:"changed_at"`
+}
+
+// priceHistoryRequest selects price changes of the product or, with nil ProductID, of the SKU
+type priceHistoryRequest struct {
+	ProductID *uuid.UUID
+	SKU       string
+	Filter    application.PriceHistoryFilter
+	PageSpec  *application.PageSpec
+}
+
+type priceHistoryResponse struct {
+	Items   []*priceChange `json:"items"`
+	HasNext bool           `json:"has_next"`
+	HasPrev bool           `json:"has_prev"`
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/jnikolaeva/eshop-common/uuid"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+func makeCreateReservationEndpoint(s application.ReservationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*createReservationRequest)
+		item, err := s.Reserve(req)
+		if err != nil {
+			return nil, err
+		}
+		return &getReservationResponse{*toReservation(item)}, nil
+	}
+}
+
+func makeGetReservationByIDEndpoint(s application.ReservationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(*uuid.UUID)
+		item, err := s.FindByID(*id)
+		if err != nil {
+			return nil, err
+		}
+		return &getReservationResponse{*toReservation(item)}, nil
+	}
+}
+
+func makeConfirmReservationEndpoint(s application.ReservationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(*uuid.UUID)
+		item, err := s.Confirm(*id)
+		if err != nil {
+			return nil, err
+		}
+		return &getReservationResponse{*toReservation(item)}, nil
+	}
+}
+
+func makeReleaseReservationEndpoint(s application.ReservationService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(*uuid.UUID)
+		item, err := s.Release(*id)
+		if err != nil {
+			return nil, err
+		}
+		return &getReservationResponse{*toReservation(item)}, nil
+	}
+}
+
+func toReservation(item *application.Reservation) *reservation {
+	result := &reservation{
+		ID:        item.ID.String(),
+		Status:    string(item.Status),
+		Items:     make([]*reservationItem, len(item.Items)),
+		CreatedAt: item.CreatedAt.UTC(),
+		ExpiresAt: item.ExpiresAt.UTC(),
+	}
+	for i, reserved := range item.Items {
+		result.Items[i] = &reservationItem{
+			ProductIDStr: reserved.ProductID.String(),
+			Quantity:     reserved.Quantity,
+		}
+		if reserved.WarehouseID != nil {
+			warehouseID := reserved.WarehouseID.String()
+			result.Items[i].WarehouseIDStr = &warehouseID
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+)
+
+func decodeCreateReservationRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req createReservationRequest
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil && e != io.EOF {
+		return nil, errors.Wrap(ErrBadRequest, e.Error())
+	}
+	if len(req.Items) == 0 {
+		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'items'")
+	}
+	for _, item := range req.Items {
+		if item.ProductID, err = uuid.FromString(item.ProductIDStr); err != nil {
+			return nil, errors.Wrapf(ErrBadRequest, "invalid product id '%s'", item.ProductIDStr)
+		}
+		if item.WarehouseID, err = parseOptionalUUID(item.WarehouseIDStr, "warehouse_id"); err != nil {
+			return nil, err
+		}
+		if item.Quantity <= 0 {
+			return nil, errors.Wrap(ErrBadRequest, "parameter 'quantity' must be positive")
+		}
+	}
+	if req.TTLSeconds < 0 {
+		return nil, errors.Wrap(ErrBadRequest, "parameter 'ttl_seconds' must not be negative")
+	}
+	return &req, nil
+}
+
+func decodeReserveProductRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	var body reserveProductRequest
+	if e := json.NewDecoder(r.Body).Decode(&body); e != nil && e != io.EOF {
+		return nil, errors.Wrap(ErrBadRequest, e.Error())
+	}
+	if body.Quantity <= 0 {
+		return nil, errors.Wrap(ErrBadRequest, "parameter 'quantity' must be positive")
+	}
+	if body.TTLSeconds < 0 {
+		return nil, errors.Wrap(ErrBadRequest, "parameter 'ttl_seconds' must not be negative")
+	}
+	warehouseID, err := parseOptionalUUID(body.WarehouseIDStr, "warehouse_id")
+	if err != nil {
+		return nil, err
+	}
+	req := &createReservationRequest{
+		Items:      []*reservationItem{{ProductID: id, WarehouseID: warehouseID, Quantity: body.Quantity}},
+		TTLSeconds: body.TTLSeconds,
+	}
+	return req, nil
+}
+
+func parseOptionalUUID(value *string, name string) (*uuid.UUID, error) {
+	if value == nil {
+		return nil, nil
+	}
+	id, err := uuid.FromString(*value)
+	if err != nil {
+		return nil, errors.Wrapf(ErrBadRequest, "invalid parameter '%s'", name)
+	}
+	return &id, nil
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+type reservation struct {
+	ID        string             `json:"id"`
+	Status    string             `json:"status"`
+	Items     []*reservationItem `json:"items"`
+	CreatedAt time.Time          `json:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at"`
+}
+
+type reservationItem struct {
+	ProductIDStr   string     `json:"product_id"`
+	ProductID      uuid.UUID  `json:"-"`
+	WarehouseIDStr *string    `json:"warehouse_id,omitempty"`
+	WarehouseID    *uuid.UUID `json:"-"`
+	Quantity       int        `json:"quantity"`
+}
+
+func (i *reservationItem) GetProductID() uuid.UUID {
+	return i.ProductID
+}
+
+func (i *reservationItem) GetWarehouseID() *uuid.UUID {
+	return i.WarehouseID
+}
+
+func (i *reservationItem) GetQuantity() int {
+	return i.Quantity
+}
+
+type createReservationRequest struct {
+	Items      []*reservationItem `json:"items"`
+	TTLSeconds int                `json:"ttl_seconds"`
+}
+
+func (c *createReservationRequest) GetItems() []application.ReservationItemParams {
+	items := make([]application.ReservationItemParams, len(c.Items))
+	for i, item := range c.Items {
+		items[i] = item
+	}
+	return items
+}
+
+func (c *createReservationRequest) GetTTL() time.Duration {
+	return time.Duration(c.TTLSeconds) * time.Second
+}
+
+type reserveProductRequest struct {
+	WarehouseIDStr *string `json:"warehouse_id"`
+	Quantity       int     `json:"quantity"`
+	TTLSeconds     int     `json:"ttl_seconds"`
+}
+
+type getReservationResponse struct {
+	reservation
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+func makeAdjustStockEndpoint(s application.StockService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*stockAdjustmentRequest)
+		item, err := s.Adjust(req.ProductID, req, req.Actor)
+		if err != nil {
+			return nil, err
+		}
+		return toStockMovement(item), nil
+	}
+}
+
+func makeGetStockMovementsEndpoint(s application.StockService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*stockMovementsRequest)
+		page, err := s.Movements(req.ProductID, req.PageSpec)
+		if err != nil {
+			return nil, err
+		}
+		res := &stockMovementsResponse{
+			Items:   make([]*stockMovement, len(page.Items)),
+			HasNext: page.HasNext,
+			HasPrev: page.HasPrev,
+		}
+		for i, item := range page.Items {
+			res.Items[i] = toStockMovement(item)
+		}
+		return res, nil
+	}
+}
+
+func toStockMovement(item *application.StockMovement) *stockMovement {
+	return &stockMovement{
+		ID:          item.ID,
+		ProductID:   item.ProductID.String(),
+		WarehouseID: item.WarehouseID.String(),
+		Delta:       item.Delta,
+		Balance:     item.Balance,
+		Reason:      string(item.Reason),
+		Actor:       item.Actor,
+		Reference:   item.Reference,
+		Note:        item.Note,
+		CreatedAt:   item.CreatedAt.UTC(),
+	}
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+func decodeStockAdjustmentRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	req := stockAdjustmentRequest{ProductID: id, Actor: r.Header.Get(actorHeader)}
+	if e := json.NewDecoder(r.Body).Decode(&req); e != nil && e != io.EOF {
+		return nil, errors.Wrap(ErrBadRequest, e.Error())
+	}
+	if req.Delta == nil {
+		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'delta'")
+	}
+	if req.Reason == "" {
+		return nil, errors.Wrap(ErrBadRequest, "missing required parameter 'reason'")
+	}
+	if req.WarehouseID, err = parseOptionalUUID(req.WarehouseIDStr, "warehouse_id"); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func decodeStockMovementsRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	return &stockMovementsRequest{ProductID: id, PageSpec: parsePageNumber(r.URL.Query())}, nil
+}
+
+// parsePageNumber reads numbered page parameters of listings without sorting and keyset cursors.
+func parsePageNumber(query url.Values) *application.PageSpec {
+	pageSize, err := strconv.Atoi(query.Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	pageNum, err := strconv.Atoi(query.Get("page_num"))
+	if err != nil || pageNum <= 0 {
+		pageNum = 1
+	}
+	return &application.PageSpec{Size: pageSize, Number: pageNum}
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+type stockMovement struct {
+	ID          int64     `json:"id"`
+	ProductID   string    `json:"product_id"`
+	WarehouseID string    `json:"warehouse_id"`
+	Delta       int       `json:"delta"`
+	Balance     int       `json:"balance"`
+	Reason      string    `json:"reason"`
+	Actor       string    `json:"actor,omitempty"`
+	Reference   string    `json:"reference,omitempty"`
+	Note        string    `json:"note,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type stockAdjustmentRequest struct {
+	ProductID      uuid.UUID
+	Actor          string
+	WarehouseIDStr *string `json:"warehouse_id"`
+	WarehouseID    *uuid.UUID
+	Delta          *int   `json:"delta"`
+	Reason         string `json:"reason"`
+	Note           string `json:"note"`
+}
+
+func (c *stockAdjustmentRequest) GetWarehouseID() *uuid.UUID {
+	return c.WarehouseID
+}
+
+func (c *stockAdjustmentRequest) GetDelta() int {
+	return *c.Delta
+}
+
+func (c *stockAdjustmentRequest) GetReason() application.StockReason {
+	return application.StockReason(c.Reason)
+}
+
+func (c *stockAdjustmentRequest) GetNote() string {
+	return c.Note
+}
+
+type stockMovementsRequest struct {
+	ProductID uuid.UUID
+	PageSpec  *application.PageSpec
+}
+
+type stockMovementsResponse struct {
+	Items   []*stockMovement `json:"items"`
+	HasNext bool             `json:"has_next"`
+	HasPrev bool             `json:"has_prev"`
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/jnikolaeva/eshop-common/uuid"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+func makeListWarehousesEndpoint(s application.WarehouseService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		items, err := s.FindAll()
+		if err != nil {
+			return nil, err
+		}
+		res := &listWarehousesResponse{Items: make([]*warehouse, len(items))}
+		for i, item := range items {
+			res.Items[i] = toWarehouse(item)
+		}
+		return res, nil
+	}
+}
+
+func makeGetWarehouseByIDEndpoint(s application.WarehouseService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(*uuid.UUID)
+		item, err := s.FindByID(*id)
+		if err != nil {
+			return nil, err
+		}
+		return &getWarehouseByIDResponse{*toWarehouse(item)}, nil
+	}
+}
+
+func makeCreateWarehouseEndpoint(s application.WarehouseService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*createWarehouseRequest)
+		id, err := s.Create(req)
+		if err != nil {
+			return nil, err
+		}
+		return &createWarehouseResponse{ID: id.String()}, nil
+	}
+}
+
+func makeUpdateWarehouseEndpoint(s application.WarehouseService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*updateWarehouseRequest)
+		item, err := s.Update(req.ID, req)
+		if err != nil {
+			return nil, err
+		}
+		return &getWarehouseByIDResponse{*toWarehouse(item)}, nil
+	}
+}
+
+func toWarehouse(item *application.Warehouse) *warehouse {
+	return &warehouse{
+		ID:        item.ID.String(),
+		Code:      item.Code,
+		Name:      item.Name,
+		Position:  item.Position,
+		IsDefault: item.IsDefault,
+	}
+}
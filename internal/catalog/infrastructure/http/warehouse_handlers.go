@@ -0,0 +1,43 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+func decodeCreateWarehouseRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req createWarehouseRequest
+	if err := decodeWarehouseParams(r, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func decodeUpdateWarehouseRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	req := updateWarehouseRequest{ID: id}
+	if err := decodeWarehouseParams(r, &req.createWarehouseRequest); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func decodeWarehouseParams(r *http.Request, req *createWarehouseRequest) error {
+	if e := json.NewDecoder(r.Body).Decode(req); e != nil && e != io.EOF {
+		return errors.Wrap(ErrBadRequest, e.Error())
+	}
+	if req.Code == "" {
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'code'")
+	}
+	if req.Name == "" {
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'name'")
+	}
+	return nil
+}
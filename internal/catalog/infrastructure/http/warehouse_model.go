@@ -0,0 +1,48 @@
+package http
+
+import (
+	"github.com/jnikolaeva/eshop-common/uuid"
+)
+
+type warehouse struct {
+	ID        string `json:"id"`
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	Position  int    `json:"position"`
+	IsDefault bool   `json:"is_default"`
+}
+
+type listWarehousesResponse struct {
+	Items []*warehouse `json:"items"`
+}
+
+type createWarehouseRequest struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+func (c *createWarehouseRequest) GetCode() string {
+	return c.Code
+}
+
+func (c *createWarehouseRequest) GetName() string {
+	return c.Name
+}
+
+func (c *createWarehouseRequest) GetPosition() int {
+	return c.Position
+}
+
+type createWarehouseResponse struct {
+	ID string `json:"id"`
+}
+
+type updateWarehouseRequest struct {
+	ID uuid.UUID
+	createWarehouseRequest
+}
+
+type getWarehouseByIDResponse struct {
+	warehouse
+}
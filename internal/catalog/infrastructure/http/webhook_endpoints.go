@@ -0,0 +1,131 @@
+package http
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/jnikolaeva/eshop-common/uuid"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+func makeListWebhooksEndpoint(s application.WebhookService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		items, err := s.FindAll()
+		if err != nil {
+			return nil, err
+		}
+		res := &listWebhooksResponse{Items: make([]*webhook, len(items))}
+		for i, item := range items {
+			res.Items[i] = toWebhook(item)
+		}
+		return res, nil
+	}
+}
+
+func makeGetWebhookByIDEndpoint(s application.WebhookService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(*uuid.UUID)
+		item, err := s.FindByID(*id)
+		if err != nil {
+			return nil, err
+		}
+		return &getWebhookByIDResponse{*toWebhook(item)}, nil
+	}
+}
+
+func makeCreateWebhookEndpoint(s application.WebhookService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*createWebhookRequest)
+		item, err := s.Create(req)
+		if err != nil {
+			return nil, err
+		}
+		return &createWebhookResponse{webhook: *toWebhook(item), Secret: item.Secret}, nil
+	}
+}
+
+func makeUpdateWebhookEndpoint(s application.WebhookService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*updateWebhookRequest)
+		item, err := s.Update(req.ID, req)
+		if err != nil {
+			return nil, err
+		}
+		return &getWebhookByIDResponse{*toWebhook(item)}, nil
+	}
+}
+
+func makeDeleteWebhookEndpoint(s application.WebhookService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(*uuid.UUID)
+		if err := s.Delete(*id); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+}
+
+func makeGetDeadLettersEndpoint(s application.WebhookService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(*deadLettersRequest)
+		page, err := s.DeadLetters(req.WebhookID, req.PageSpec)
+		if err != nil {
+			return nil, err
+		}
+		res := &deadLettersResponse{
+			Items:   make([]*webhookDelivery, len(page.Items)),
+			HasNext: page.HasNext,
+			HasPrev: page.HasPrev,
+		}
+		for i, item := range page.Items {
+			res.Items[i] = toWebhookDelivery(item)
+		}
+		return res, nil
+	}
+}
+
+func makeRedeliverDeadLetterEndpoint(s application.WebhookService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id := request.(int64)
+		item, err := s.Redeliver(id)
+		if err != nil {
+			return nil, err
+		}
+		return toWebhookDelivery(item), nil
+	}
+}
+
+func toWebhook(item *application.Webhook) *webhook {
+	result := &webhook{
+		ID:         item.ID.String(),
+		URL:        item.URL,
+		EventTypes: make([]string, len(item.EventTypes)),
+		ProductIDs: make([]string, len(item.ProductIDs)),
+		Active:     item.Active,
+		CreatedAt:  item.CreatedAt.UTC(),
+	}
+	for i, t := range item.EventTypes {
+		result.EventTypes[i] = string(t)
+	}
+	for i, id := range item.ProductIDs {
+		result.ProductIDs[i] = id.String()
+	}
+	return result
+}
+
+func toWebhookDelivery(item *application.WebhookDelivery) *webhookDelivery {
+	return &webhookDelivery{
+		ID:            item.ID,
+		WebhookID:     item.WebhookID.String(),
+		EventID:       item.Event.ID.String(),
+		EventType:     string(item.Event.Type),
+		AggregateID:   item.Event.AggregateID.String(),
+		Payload:       item.Event.Payload,
+		OccurredAt:    item.Event.OccurredAt.UTC(),
+		Status:        string(item.Status),
+		Attempts:      item.Attempts,
+		NextAttemptAt: item.NextAttemptAt.UTC(),
+		LastError:     item.LastError,
+	}
+}
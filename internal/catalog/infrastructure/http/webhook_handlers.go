@@ -0,0 +1,76 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+)
+
+func decodeCreateWebhookRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	var req createWebhookRequest
+	if err := decodeWebhookParams(r, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func decodeUpdateWebhookRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	id, err := decodePathID(r)
+	if err != nil {
+		return nil, err
+	}
+	req := updateWebhookRequest{ID: id}
+	if err := decodeWebhookParams(r, &req.createWebhookRequest); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func decodeWebhookParams(r *http.Request, req *createWebhookRequest) error {
+	if e := json.NewDecoder(r.Body).Decode(req); e != nil && e != io.EOF {
+		return errors.Wrap(ErrBadRequest, e.Error())
+	}
+	if req.URL == "" {
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'url'")
+	}
+	if len(req.EventTypes) == 0 {
+		return errors.Wrap(ErrBadRequest, "missing required parameter 'event_types'")
+	}
+	for _, s := range req.ProductIDStrs {
+		id, err := uuid.FromString(s)
+		if err != nil {
+			return errors.Wrapf(ErrBadRequest, "invalid product id '%s'", s)
+		}
+		req.ProductIDs = append(req.ProductIDs, id)
+	}
+	return nil
+}
+
+func decodeDeadLettersRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	query := r.URL.Query()
+	req := &deadLettersRequest{PageSpec: parsePageNumber(query)}
+	if value := query.Get("webhook_id"); value != "" {
+		if req.WebhookID, err = parseOptionalUUID(&value, "webhook_id"); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+func decodeDeadLetterIDRequest(_ context.Context, r *http.Request) (request interface{}, err error) {
+	sID, ok := mux.Vars(r)["id"]
+	if !ok {
+		return nil, ErrBadRouting
+	}
+	id, err := strconv.ParseInt(sID, 10, 64)
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	return id, nil
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jnikolaeva/eshop-common/uuid"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+type webhook struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	ProductIDs []string  `json:"product_ids"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type listWebhooksResponse struct {
+	Items []*webhook `json:"items"`
+}
+
+type createWebhookRequest struct {
+	URL           string      `json:"url"`
+	EventTypes    []string    `json:"event_types"`
+	ProductIDStrs []string    `json:"product_ids"`
+	ProductIDs    []uuid.UUID `json:"-"`
+	Secret        string      `json:"secret"`
+	Active        *bool       `json:"active"`
+}
+
+func (c *createWebhookRequest) GetURL() string {
+	return c.URL
+}
+
+func (c *createWebhookRequest) GetEventTypes() []application.EventType {
+	types := make([]application.EventType, len(c.EventTypes))
+	for i, t := range c.EventTypes {
+		types[i] = application.EventType(t)
+	}
+	return types
+}
+
+func (c *createWebhookRequest) GetProductIDs() []uuid.UUID {
+	return c.ProductIDs
+}
+
+func (c *createWebhookRequest) GetSecret() string {
+	return c.Secret
+}
+
+func (c *createWebhookRequest) GetActive() bool {
+	return c.Active == nil || *c.Active
+}
+
+// createWebhookResponse is the only response exposing the secret
+type createWebhookResponse struct {
+	webhook
+	Secret string `json:"secret"`
+}
+
+type updateWebhookRequest struct {
+	ID uuid.UUID
+	createWebhookRequest
+}
+
+type getWebhookByIDResponse struct {
+	webhook
+}
+
+type webhookDelivery struct {
+	ID            int64           `json:"id"`
+	WebhookID     string          `json:"webhook_id"`
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     string          `json:"last_error,omitempty"`
+}
+
+type deadLettersRequest struct {
+	WebhookID *uuid.UUID
+	PageSpec  *application.PageSpec
+}
+
+type deadLettersResponse struct {
+	Items   []*webhookDelivery `json:"items"`
+	HasNext bool               `json:"has_next"`
+	HasPrev bool               `json:"has_prev"`
+}
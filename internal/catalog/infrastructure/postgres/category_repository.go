@@ -0,0 +1,228 @@
+package postgres
+
+import (
+	"github.com/jackc/pgx"
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+const (
+	errForeignKeyViolation = "23503"
+
+	productCategoriesProductFK  = "product_categories_product_id_fkey"
+	productCategoriesCategoryFK = "product_categories_category_id_fkey"
+	categoriesParentFK          = "categories_parent_id_fkey"
+)
+
+type rawCategory struct {
+	ID       string  `db:"id"`
+	ParentID *string `db:"parent_id"`
+	Slug     string  `db:"slug"`
+	Name     string  `db:"name"`
+	Position int     `db:"position"`
+}
+
+type categoryRepository struct {
+	connPool *pgx.ConnPool
+}
+
+func NewCategoryRepository(connPool *pgx.ConnPool) application.CategoryRepository {
+	return &categoryRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *categoryRepository) NextID() application.CategoryID {
+	return application.CategoryID(uuid.Generate())
+}
+
+func (r *categoryRepository) FindByID(id application.CategoryID) (*application.Category, error) {
+	var raw rawCategory
+	query := "SELECT id, parent_id, slug, name, position FROM categories WHERE id = $1"
+	err := r.connPool.QueryRow(query, id.String()).Scan(&raw.ID, &raw.ParentID, &raw.Slug, &raw.Name, &raw.Position)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = application.ErrCategoryNotFound
+		}
+		return nil, errors.WithStack(err)
+	}
+	return mapToCategory(raw), nil
+}
+
+func (r *categoryRepository) FindAll() ([]*application.Category, error) {
+	return r.find("SELECT id, parent_id, slug, name, position FROM categories ORDER BY position, name")
+}
+
+func (r *categoryRepository) FindByProduct(productID application.ProductID) ([]*application.Category, error) {
+	var exists bool
+	err := r.connPool.QueryRow("SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", productID.String()).Scan(&exists)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !exists {
+		return nil, application.ErrProductNotFound
+	}
+	return r.find(`SELECT c.id, c.parent_id, c.slug, c.name, c.position FROM categories c
+			JOIN product_categories pc ON pc.category_id = c.id
+			WHERE pc.product_id = $1 ORDER BY c.position, c.name`, productID.String())
+}
+
+func (r *categoryRepository) Add(item application.Category) error {
+	_, err := r.connPool.Exec(
+		"INSERT INTO categories (id, parent_id, slug, name, position) VALUES ($1, $2, $3, $4, $5)",
+		item.ID.String(),
+		parentIDString(item.ParentID),
+		item.Slug,
+		item.Name,
+		item.Position)
+	return translateCategoryError(err)
+}
+
+// Update serializes category updates with a table lock and checks the new parent again inside the transaction,
+// so concurrent parent changes can't create a cycle.
+func (r *categoryRepository) Update(item application.Category) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec("LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return errors.WithStack(err)
+	}
+	if item.ParentID != nil {
+		var cycle bool
+		err = tx.QueryRow(
+			`WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM categories WHERE id = $1
+				UNION
+				SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)`,
+			item.ParentID.String(),
+			item.ID.String()).Scan(&cycle)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if cycle {
+			return application.ErrInvalidParent
+		}
+	}
+	tag, err := tx.Exec(
+		"UPDATE categories SET parent_id = $2, slug = $3, name = $4, position = $5 WHERE id = $1",
+		item.ID.String(),
+		parentIDString(item.ParentID),
+		item.Slug,
+		item.Name,
+		item.Position)
+	if err != nil {
+		return translateCategoryError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return application.ErrCategoryNotFound
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+func (r *categoryRepository) Delete(id application.CategoryID) error {
+	tag, err := r.connPool.Exec("DELETE FROM categories WHERE id = $1", id.String())
+	if err != nil {
+		pgErr, ok := err.(pgx.PgError)
+		if ok && pgErr.Code == errForeignKeyViolation && pgErr.ConstraintName == categoriesParentFK {
+			return application.ErrCategoryHasChildren
+		}
+		return errors.WithStack(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return application.ErrCategoryNotFound
+	}
+	return nil
+}
+
+func (r *categoryRepository) SetProductCategories(productID application.ProductID, ids []application.CategoryID) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec("DELETE FROM product_categories WHERE product_id = $1", productID.String()); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, id := range ids {
+		_, err = tx.Exec(
+			"INSERT INTO product_categories (product_id, category_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			productID.String(),
+			id.String())
+		if err != nil {
+			return translateCategoryError(err)
+		}
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+func (r *categoryRepository) find(query string, args ...interface{}) ([]*application.Category, error) {
+	rows, err := r.connPool.Query(query, args...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	var items []*application.Category
+	var raw rawCategory
+	for rows.Next() {
+		raw.ParentID = nil
+		if err = rows.Scan(&raw.ID, &raw.ParentID, &raw.Slug, &raw.Name, &raw.Position); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		items = append(items, mapToCategory(raw))
+	}
+	return items, errors.WithStack(rows.Err())
+}
+
+func mapToCategory(raw rawCategory) *application.Category {
+	itemID, _ := uuid.FromString(raw.ID)
+	item := &application.Category{
+		ID:       application.CategoryID(itemID),
+		Slug:     raw.Slug,
+		Name:     raw.Name,
+		Position: raw.Position,
+	}
+	if raw.ParentID != nil {
+		parentID, _ := uuid.FromString(*raw.ParentID)
+		categoryID := application.CategoryID(parentID)
+		item.ParentID = &categoryID
+	}
+	return item
+}
+
+func parentIDString(id *application.CategoryID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+func translateCategoryError(err error) error {
+	if err == nil {
+		return nil
+	}
+	pgErr, ok := err.(pgx.PgError)
+	if !ok {
+		return errors.WithStack(err)
+	}
+	switch {
+	case pgErr.Code == errUniqueConstraint:
+		return application.ErrDuplicateCategory
+	case pgErr.Code == errForeignKeyViolation && pgErr.ConstraintName == productCategoriesProductFK:
+		return application.ErrProductNotFound
+	case pgErr.Code == errForeignKeyViolation && pgErr.ConstraintName == productCategoriesCategoryFK:
+		return application.ErrCategoryNotFound
+	case pgErr.Code == errForeignKeyViolation && pgErr.ConstraintName == categoriesParentFK:
+		return application.ErrInvalidParent
+	}
+	return errors.WithStack(err)
+}
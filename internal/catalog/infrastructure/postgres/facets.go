@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+// Facets counts products for every facet value with the filters applied, except the filter on the facet itself,
+// so that the counts show what selecting another value would give.
+func (r *repository) Facets(filters *application.Filters, spec application.FacetSpec) (*application.Facets, error) {
+	var facets application.Facets
+	var err error
+	grouped := filters != nil && filters.GroupVariants
+	if spec.Contains(application.FacetColor) {
+		f := facetFilters(filters)
+		f.Color = nil
+		if facets.Color, err = r.countValues("p.color", f, grouped); err != nil {
+			return nil, err
+		}
+	}
+	if spec.Contains(application.FacetMaterial) {
+		f := facetFilters(filters)
+		f.Material = nil
+		if facets.Material, err = r.countValues("p.material", f, grouped); err != nil {
+			return nil, err
+		}
+	}
+	if spec.Contains(application.FacetPrice) {
+		f := facetFilters(filters)
+		f.Price = application.DecimalRangeFilter{}
+		if facets.Price, err = r.countPriceBuckets(spec.PriceBuckets, f, grouped); err != nil {
+			return nil, err
+		}
+	}
+	return &facets, nil
+}
+
+func (r *repository) countValues(column string, filters *application.Filters, grouped bool) ([]application.FacetValue, error) {
+	prices, args := newPricing(filters.Pricing, nil)
+	conditions, args := applyFilters(filters, prices, args)
+	conditions = append(conditions, column+" <> ''")
+	query := fmt.Sprintf("SELECT %s, %s FROM %s%s GROUP BY 1 ORDER BY 2 DESC, 1",
+		column, countExpr(grouped), prices.source(), whereClause(conditions))
+
+	rows, err := r.connPool.Query(query, args...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	var values []application.FacetValue
+	for rows.Next() {
+		var value application.FacetValue
+		if err = rows.Scan(&value.Value, &value.Count); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		values = append(values, value)
+	}
+	return values, errors.WithStack(rows.Err())
+}
+
+func (r *repository) countPriceBuckets(bounds []decimal.Decimal, filters *application.Filters, grouped bool) ([]application.PriceBucket, error) {
+	prices, args := newPricing(filters.Pricing, nil)
+	conditions, args := applyFilters(filters, prices, args)
+	args = append(args, numericArray(bounds))
+	// width_bucket returns 0 for prices below the first bound and len(bounds) for prices above the last one
+	query := fmt.Sprintf("SELECT width_bucket(pl.price, $%d::numeric[]), %s FROM %s%s GROUP BY 1",
+		len(args), countExpr(grouped), prices.source(), whereClause(conditions))
+
+	rows, err := r.connPool.Query(query, args...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	buckets := make([]application.PriceBucket, len(bounds)+1)
+	for i := range buckets {
+		if i > 0 {
+			buckets[i].From = &bounds[i-1]
+		}
+		if i < len(bounds) {
+			buckets[i].To = &bounds[i]
+		}
+	}
+	for rows.Next() {
+		var bucket, count int
+		if err = rows.Scan(&bucket, &count); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		buckets[bucket].Count = count
+	}
+	return buckets, errors.WithStack(rows.Err())
+}
+
+// facetFilters copies the filters without variant grouping, grouped listings count distinct parents instead.
+func facetFilters(filters *application.Filters) *application.Filters {
+	var f application.Filters
+	if filters != nil {
+		f = *filters
+	}
+	f.GroupVariants = false
+	return &f
+}
+
+func countExpr(grouped bool) string {
+	if grouped {
+		return "count(DISTINCT COALESCE(p.parent_id, p.id))"
+	}
+	return "count(*)"
+}
+
+func numericArray(values []decimal.Decimal) string {
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = v.String()
+	}
+	return "{" + strings.Join(items, ",") + "}"
+}
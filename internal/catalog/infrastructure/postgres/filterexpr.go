@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+type filterColumn struct {
+	expr string
+	cast string
+}
+
+var filterColumns = map[application.FilterField]filterColumn{
+	application.FilterFieldTitle:        {expr: "p.title", cast: "text"},
+	application.FilterFieldSKU:          {expr: "p.sku", cast: "text"},
+	application.FilterFieldPrice:        {expr: "pl.price", cast: "numeric"},
+	application.FilterFieldAvailableQty: {expr: "p.available_qty", cast: "integer"},
+	application.FilterFieldColor:        {expr: "p.color", cast: "text"},
+	application.FilterFieldMaterial:     {expr: "p.material", cast: "text"},
+	application.FilterFieldParentID:     {expr: "p.parent_id", cast: "uuid"},
+}
+
+var compareOperators = map[application.CompareOp]string{
+	application.OpEq:  "=",
+	application.OpNe:  "<>",
+	application.OpGt:  ">",
+	application.OpGte: ">=",
+	application.OpLt:  "<",
+	application.OpLte: "<=",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `*`, `%`)
+
+// compileFilterExpr translates the parsed expression into SQL, every value is passed as a query parameter.
+func compileFilterExpr(expr application.FilterExpr, args []interface{}) (string, []interface{}) {
+	switch e := expr.(type) {
+	case *application.LogicalExpr:
+		operands := make([]string, len(e.Operands))
+		for i, operand := range e.Operands {
+			operands[i], args = compileFilterExpr(operand, args)
+		}
+		return "(" + strings.Join(operands, " "+strings.ToUpper(string(e.Op))+" ") + ")", args
+	case *application.NotExpr:
+		operand, args := compileFilterExpr(e.Operand, args)
+		return "NOT " + operand, args
+	case *application.Comparison:
+		return compileComparison(e, args)
+	}
+	return "FALSE", args
+}
+
+func compileComparison(cmp *application.Comparison, args []interface{}) (string, []interface{}) {
+	column := filterColumns[cmp.Field]
+	param := func(value string) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d::%s", len(args), column.cast)
+	}
+	switch cmp.Op {
+	case application.OpExists:
+		if cmp.Field.Type() == application.FilterTypeString {
+			return fmt.Sprintf("(%s <> '')", column.expr), args
+		}
+		return fmt.Sprintf("(%s IS NOT NULL)", column.expr), args
+	case application.OpLike:
+		return fmt.Sprintf("(%s ILIKE %s)", column.expr, param(likeEscaper.Replace(cmp.Values[0]))), args
+	case application.OpIn, application.OpNin:
+		params := make([]string, len(cmp.Values))
+		for i, value := range cmp.Values {
+			params[i] = param(value)
+		}
+		operator := "IN"
+		if cmp.Op == application.OpNin {
+			operator = "NOT IN"
+		}
+		return fmt.Sprintf("(%s %s (%s))", column.expr, operator, strings.Join(params, ", ")), args
+	}
+	return fmt.Sprintf("(%s %s %s)", column.expr, compareOperators[cmp.Op], param(cmp.Values[0])), args
+}
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx"
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+const importJobColumns = `id, format, actor, status, total_rows, processed_rows, created_count, updated_count, failed_count,
+	error, created_at, updated_at, finished_at`
+
+type importJobRepository struct {
+	connPool *pgx.ConnPool
+}
+
+func NewImportJobRepository(connPool *pgx.ConnPool) application.ImportJobRepository {
+	return &importJobRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *importJobRepository) NextID() application.ImportJobID {
+	return application.ImportJobID(uuid.Generate())
+}
+
+func (r *importJobRepository) Add(job application.ImportJob, content []byte) error {
+	_, err := r.connPool.Exec(
+		`INSERT INTO import_jobs (id, format, actor, status, total_rows, content) VALUES ($1, $2, $3, $4, $5, $6)`,
+		job.ID.String(),
+		job.Format,
+		job.Actor,
+		string(job.Status),
+		job.TotalRows,
+		content)
+	return errors.WithStack(err)
+}
+
+func (r *importJobRepository) FindByID(id application.ImportJobID) (*application.ImportJob, error) {
+	job, err := scanImportJob(r.connPool.QueryRow("SELECT "+importJobColumns+" FROM import_jobs WHERE id = $1", id.String()))
+	if errors.Cause(err) == pgx.ErrNoRows {
+		return nil, application.ErrImportJobNotFound
+	}
+	return job, err
+}
+
+func (r *importJobRepository) FindErrors(id application.ImportJobID) ([]application.ImportRowResult, error) {
+	if _, err := r.FindByID(id); err != nil {
+		return nil, err
+	}
+	rows, err := r.connPool.Query(
+		"SELECT row_number, sku, message FROM import_job_errors WHERE job_id = $1 ORDER BY row_number", id.String())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	var results []application.ImportRowResult
+	for rows.Next() {
+		result := application.ImportRowResult{Status: application.ImportStatusFailed}
+		var message string
+		if err = rows.Scan(&result.Number, &result.SKU, &message); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		result.Err = errors.New(message)
+		results = append(results, result)
+	}
+	return results, errors.WithStack(rows.Err())
+}
+
+func (r *importJobRepository) ClaimNext(lease time.Duration) (*application.ImportJob, []byte, error) {
+	var content []byte
+	job, err := scanImportJob(r.connPool.QueryRow(
+		`UPDATE import_jobs SET status = $1, locked_until = now() + $2 * interval '1 millisecond', updated_at = now()
+			WHERE id = (
+				SELECT id FROM import_jobs WHERE status = $3 OR (status = $1 AND locked_until < now())
+				ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED
+			)
+			RETURNING `+importJobColumns+`, content`,
+		string(application.ImportJobRunning),
+		lease.Milliseconds(),
+		string(application.ImportJobPending)), &content)
+	if errors.Cause(err) == pgx.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, content, nil
+}
+
+// Finish drops the file of the job, it is not needed once the job has finished.
+func (r *importJobRepository) Finish(id application.ImportJobID, status application.ImportJobStatus, message string) error {
+	_, err := r.connPool.Exec(
+		`UPDATE import_jobs SET status = $2, error = $3, content = '', locked_until = NULL, finished_at = now(),
+			updated_at = now() WHERE id = $1 AND status = $4`,
+		id.String(),
+		string(status),
+		message,
+		string(application.ImportJobRunning))
+	return errors.WithStack(err)
+}
+
+// saveImportCheckpoint moves the job past the batch committed in tx and extends the lease of the worker.
+func saveImportCheckpoint(tx *pgx.Tx, checkpoint *application.ImportCheckpoint) error {
+	tag, err := tx.Exec(
+		`UPDATE import_jobs SET processed_rows = $3, created_count = created_count + $4,
+			updated_count = updated_count + $5, failed_count = failed_count + $6,
+			locked_until = now() + $7 * interval '1 millisecond', updated_at = now()
+			WHERE id = $1 AND processed_rows = $2 AND status = $8`,
+		checkpoint.JobID.String(),
+		checkpoint.From,
+		checkpoint.To,
+		checkpoint.Created,
+		checkpoint.Updated,
+		checkpoint.Failed,
+		checkpoint.Lease.Milliseconds(),
+		string(application.ImportJobRunning))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return application.ErrImportJobLost
+	}
+	if len(checkpoint.Errors) == 0 {
+		return nil
+	}
+	numbers := make([]string, len(checkpoint.Errors))
+	skus := make([]string, len(checkpoint.Errors))
+	messages := make([]string, len(checkpoint.Errors))
+	for i, result := range checkpoint.Errors {
+		numbers[i] = strconv.Itoa(result.Number)
+		skus[i] = result.SKU
+		messages[i] = result.Err.Error()
+	}
+	_, err = tx.Exec(
+		`INSERT INTO import_job_errors (job_id, row_number, sku, message)
+			SELECT $1, unnest($2::integer[]), unnest($3::text[]), unnest($4::text[])`,
+		checkpoint.JobID.String(),
+		arrayLiteral(numbers),
+		arrayLiteral(skus),
+		arrayLiteral(messages))
+	return errors.WithStack(err)
+}
+
+func scanImportJob(row scanner, extra ...interface{}) (*application.ImportJob, error) {
+	var id, status string
+	job := &application.ImportJob{}
+	dest := []interface{}{
+		&id,
+		&job.Format,
+		&job.Actor,
+		&status,
+		&job.TotalRows,
+		&job.ProcessedRows,
+		&job.Created,
+		&job.Updated,
+		&job.Failed,
+		&job.Error,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&job.FinishedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	jobID, _ := uuid.FromString(id)
+	job.ID = application.ImportJobID(jobID)
+	job.Status = application.ImportJobStatus(status)
+	return job, nil
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx"
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+type productPayload struct {
+	ID           string            `json:"id"`
+	Version      int               `json:"version"`
+	Title        string            `json:"title"`
+	SKU          string            `json:"sku"`
+	Price        decimal.Decimal   `json:"price"`
+	Currency     string            `json:"currency"`
+	AvailableQty int               `json:"available_qty"`
+	ImageURL     string            `json:"image_url"`
+	Color        string            `json:"color"`
+	Material     string            `json:"material"`
+	ParentID     *string           `json:"parent_id,omitempty"`
+	VariantAxes  []string          `json:"variant_axes,omitempty"`
+	Options      map[string]string `json:"options,omitempty"`
+}
+
+type productDeletedPayload struct {
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
+
+type stockChangedPayload struct {
+	ProductID   string `json:"product_id"`
+	WarehouseID string `json:"warehouse_id"`
+	Delta       int    `json:"delta"`
+	Balance     int    `json:"balance"`
+	Reason      string `json:"reason"`
+	Reference   string `json:"reference,omitempty"`
+}
+
+type outboxRepository struct {
+	connPool *pgx.ConnPool
+}
+
+func NewOutboxRepository(connPool *pgx.ConnPool) application.OutboxRepository {
+	return &outboxRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *outboxRepository) PublishPending(limit int, publish func(events []application.Event) error) (int, error) {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		`SELECT id, event_id, event_type, aggregate_id, payload::text, occurred_at FROM outbox
+			WHERE published_at IS NULL ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED`,
+		limit)
+	if err != nil {
+		return 0, errors.WithMessage(err, "Database error")
+	}
+	var ids []string
+	var events []application.Event
+	for rows.Next() {
+		var id int64
+		var eventID, eventType, aggregateID, payload string
+		var event application.Event
+		if err = rows.Scan(&id, &eventID, &eventType, &aggregateID, &payload, &event.OccurredAt); err != nil {
+			rows.Close()
+			return 0, errors.WithStack(err)
+		}
+		event.ID, _ = uuid.FromString(eventID)
+		event.Type = application.EventType(eventType)
+		productID, _ := uuid.FromString(aggregateID)
+		event.AggregateID = application.ProductID(productID)
+		event.Payload = []byte(payload)
+		ids = append(ids, fmt.Sprint(id))
+		events = append(events, event)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	if err = publish(events); err != nil {
+		return 0, err
+	}
+	_, err = tx.Exec("UPDATE outbox SET published_at = now() WHERE id = ANY($1::bigint[])", arrayLiteral(ids))
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return len(events), errors.WithStack(tx.Commit())
+}
+
+func insertEvent(tx *pgx.Tx, eventType application.EventType, aggregateID application.ProductID, payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	eventID := uuid.Generate().String()
+	occurredAt := time.Now()
+	_, err = tx.Exec(
+		"INSERT INTO outbox (event_id, event_type, aggregate_id, payload, occurred_at) VALUES ($1, $2, $3, $4::jsonb, $5)",
+		eventID,
+		string(eventType),
+		aggregateID.String(),
+		string(b),
+		occurredAt)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return queueWebhookDeliveries(tx, eventID, eventType, aggregateID, string(b), occurredAt)
+}
+
+func insertProductEvent(tx *pgx.Tx, eventType application.EventType, item application.Product, version int) error {
+	return insertEvent(tx, eventType, item.ID, newProductPayload(item, version))
+}
+
+func newProductPayload(item application.Product, version int) productPayload {
+	payload := productPayload{
+		ID:           item.ID.String(),
+		Version:      version,
+		Title:        item.Title,
+		SKU:          item.SKU,
+		Price:        item.Price,
+		Currency:     item.Currency,
+		AvailableQty: item.AvailableQty,
+		Color:        item.Color,
+		Material:     item.Material,
+		ParentID:     productIDString(item.ParentID),
+		VariantAxes:  item.VariantAxes,
+		Options:      item.Options,
+	}
+	if item.Image != nil {
+		payload.ImageURL = item.Image.URL
+	}
+	return payload
+}
+
+// outboxEvent is an event written to the outbox together with others by insertEvents.
+type outboxEvent struct {
+	Type        application.EventType
+	AggregateID application.ProductID
+	Payload     interface{}
+}
+
+// insertEvents writes the events to the outbox in their order and queues their webhook deliveries
+// with one statement each whatever the number of events.
+func insertEvents(tx *pgx.Tx, events []outboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	rows := make([][]string, len(events))
+	for i, event := range events {
+		b, err := json.Marshal(event.Payload)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		rows[i] = []string{uuid.Generate().String(), string(event.Type), event.AggregateID.String(), string(b)}
+	}
+	args := append(arrayArgs(rows), time.Now())
+	_, err := tx.Exec(
+		`INSERT INTO outbox (event_id, event_type, aggregate_id, payload, occurred_at)
+			SELECT event_id, event_type, aggregate_id, payload::jsonb, $5::timestamptz
+			FROM unnest($1::uuid[], $2::text[], $3::uuid[], $4::text[]) WITH ORDINALITY
+				AS t(event_id, event_type, aggregate_id, payload, n)
+			ORDER BY n`,
+		args...)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return queueEventsWebhookDeliveries(tx, args)
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx"
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+// recordPriceChange appends the change to the price history unless the price stays the same, the current SKU
+// of the product is recorded with it. Without a currency the one of the price list or of the product is recorded.
+func recordPriceChange(tx *pgx.Tx, item *application.PriceChange) error {
+	if item.Reason == application.PriceChangeReasonUpdate &&
+		item.OldPrice != nil && item.NewPrice != nil && item.OldPrice.Equal(*item.NewPrice) {
+		return nil
+	}
+	var listID, scheduledPriceID *string
+	if item.PriceListID != nil {
+		id := item.PriceListID.String()
+		listID = &id
+	}
+	if item.ScheduledPriceID != nil {
+		id := item.ScheduledPriceID.String()
+		scheduledPriceID = &id
+	}
+	err := tx.QueryRow(
+		`INSERT INTO price_changes (product_id, sku, price_list_id, scheduled_price_id, reason, currency,
+				old_price, new_price, starts_at, ends_at, actor)
+			SELECT p.id, p.sku, $2::uuid, $3::uuid, $4,
+				COALESCE($5::text, (SELECT l.currency FROM price_lists l WHERE l.id = $2::uuid), p.currency),
+				$6, $7, $8::timestamptz, $9::timestamptz, $10
+			FROM products p WHERE p.id = $1
+			RETURNING id, sku, currency, changed_at`,
+		item.ProductID.String(),
+		listID,
+		scheduledPriceID,
+		string(item.Reason),
+		nullString(item.Currency),
+		item.OldPrice,
+		item.NewPrice,
+		item.StartsAt,
+		item.EndsAt,
+		nullString(item.Actor)).Scan(&item.ID, &item.SKU, &item.Currency, &item.ChangedAt)
+	if err == pgx.ErrNoRows {
+		return application.ErrProductNotFound
+	}
+	return errors.WithStack(err)
+}
+
+func (r *repository) FindPriceHistory(filter application.PriceHistoryFilter,
+	spec *application.PageSpec) (*application.PriceChangesPage, error) {
+	column, value := "sku", filter.SKU
+	if filter.ProductID != nil {
+		column, value = "product_id", filter.ProductID.String()
+	}
+	// the history of a deleted product is still found
+	var exists bool
+	err := r.connPool.QueryRow(
+		fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM products WHERE %[1]s = $1) OR EXISTS (SELECT 1 FROM price_changes WHERE %[1]s = $1)", column),
+		value).Scan(&exists)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !exists {
+		return nil, application.ErrProductNotFound
+	}
+
+	conditions := []string{column + " = $1"}
+	args := []interface{}{value}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("changed_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("changed_at < $%d", len(args)))
+	}
+	query := fmt.Sprintf(
+		`SELECT id, product_id, sku, price_list_id, scheduled_price_id, reason, currency, old_price, new_price,
+			starts_at, ends_at, actor, changed_at FROM price_changes%s
+			ORDER BY changed_at DESC, id DESC LIMIT %d OFFSET %d`,
+		whereClause(conditions), spec.Size+1, (spec.Number-1)*spec.Size)
+	rows, err := r.connPool.Query(query, args...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	page := &application.PriceChangesPage{HasPrev: spec.Number > 1}
+	for rows.Next() {
+		item := &application.PriceChange{}
+		var productID string
+		var reason string
+		var listID, scheduledPriceID, actor *string
+		var oldPrice, newPrice decimal.NullDecimal
+		err = rows.Scan(&item.ID, &productID, &item.SKU, &listID, &scheduledPriceID, &reason, &item.Currency,
+			&oldPrice, &newPrice, &item.StartsAt, &item.EndsAt, &actor, &item.ChangedAt)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		item.Reason = application.PriceChangeReason(reason)
+		if scheduledPriceID != nil {
+			parsed, _ := uuid.FromString(*scheduledPriceID)
+			id := application.ScheduledPriceID(parsed)
+			item.ScheduledPriceID = &id
+		}
+		item.ProductID = parseProductID(productID)
+		if listID != nil {
+			parsed, _ := uuid.FromString(*listID)
+			priceListID := application.PriceListID(parsed)
+			item.PriceListID = &priceListID
+		}
+		item.OldPrice = decimalValue(oldPrice)
+		item.NewPrice = decimalValue(newPrice)
+		item.Actor = stringValue(actor)
+		page.Items = append(page.Items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(page.Items) > spec.Size {
+		page.Items = page.Items[:spec.Size]
+		page.HasNext = true
+	}
+	return page, nil
+}
+
+func decimalValue(d decimal.NullDecimal) *decimal.Decimal {
+	if !d.Valid {
+		return nil
+	}
+	return &d.Decimal
+}
+
+func parseProductID(s string) application.ProductID {
+	id, _ := uuid.FromString(s)
+	return application.ProductID(id)
+}
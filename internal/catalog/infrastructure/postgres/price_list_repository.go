@@ -0,0 +1,426 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx"
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+const (
+	priceListEntriesListFK    = "price_list_entries_price_list_id_fkey"
+	priceListEntriesProductFK = "price_list_entries_product_id_fkey"
+	productPricesListFK       = "product_prices_price_list_id_fkey"
+	productPricesProductFK    = "product_prices_product_id_fkey"
+)
+
+type priceListRepository struct {
+	connPool *pgx.ConnPool
+}
+
+func NewPriceListRepository(connPool *pgx.ConnPool) application.PriceListRepository {
+	return &priceListRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *priceListRepository) NextID() application.PriceListID {
+	return application.PriceListID(uuid.Generate())
+}
+
+func (r *priceListRepository) FindByID(id application.PriceListID) (*application.PriceList, error) {
+	items, err := r.find("SELECT id, name, currency, market, customer_group FROM price_lists WHERE id = $1", id.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, application.ErrPriceListNotFound
+	}
+	return items[0], nil
+}
+
+func (r *priceListRepository) FindAll() ([]*application.PriceList, error) {
+	return r.find("SELECT id, name, currency, market, customer_group FROM price_lists ORDER BY currency, market, customer_group")
+}
+
+func (r *priceListRepository) Add(item application.PriceList) error {
+	_, err := r.connPool.Exec(
+		"INSERT INTO price_lists (id, name, currency, market, customer_group) VALUES ($1, $2, $3, $4, $5)",
+		item.ID.String(),
+		item.Name,
+		item.Currency,
+		item.Market,
+		item.CustomerGroup)
+	return translatePriceListError(err)
+}
+
+// Delete removes the price list with its prices and scheduled prices recording the removed ones in the price history.
+func (r *priceListRepository) Delete(id application.PriceListID, actor string) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	var currency string
+	err = tx.QueryRow("SELECT currency FROM price_lists WHERE id = $1 FOR UPDATE", id.String()).Scan(&currency)
+	if err == pgx.ErrNoRows {
+		return application.ErrPriceListNotFound
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	var changes []*application.PriceChange
+	rows, err := tx.Query(
+		`DELETE FROM product_prices WHERE price_list_id = $1
+			RETURNING product_id, id, price, starts_at, ends_at`,
+		id.String())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for rows.Next() {
+		var productID, scheduledPriceID string
+		change := &application.PriceChange{}
+		change.OldPrice = &decimal.Decimal{}
+		change.StartsAt = &time.Time{}
+		if err = rows.Scan(&productID, &scheduledPriceID, change.OldPrice, change.StartsAt, &change.EndsAt); err != nil {
+			rows.Close()
+			return errors.WithStack(err)
+		}
+		parsedID, _ := uuid.FromString(scheduledPriceID)
+		priceID := application.ScheduledPriceID(parsedID)
+		change.ScheduledPriceID = &priceID
+		change.ProductID = parseProductID(productID)
+		changes = append(changes, change)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return errors.WithStack(err)
+	}
+	rows, err = tx.Query("DELETE FROM price_list_entries WHERE price_list_id = $1 RETURNING product_id, price", id.String())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for rows.Next() {
+		var productID string
+		change := &application.PriceChange{OldPrice: &decimal.Decimal{}}
+		if err = rows.Scan(&productID, change.OldPrice); err != nil {
+			rows.Close()
+			return errors.WithStack(err)
+		}
+		change.ProductID = parseProductID(productID)
+		changes = append(changes, change)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, change := range changes {
+		change.PriceListID = &id
+		change.Reason = application.PriceChangeReasonPriceListDeletion
+		change.Currency = currency
+		change.Actor = actor
+		if err = recordPriceChange(tx, change); err != nil {
+			return err
+		}
+	}
+	if _, err = tx.Exec("DELETE FROM price_lists WHERE id = $1", id.String()); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+func (r *priceListRepository) SetPrice(id application.PriceListID, productID application.ProductID, price decimal.Decimal,
+	actor string) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	var oldPrice decimal.NullDecimal
+	err = tx.QueryRow("SELECT price FROM price_list_entries WHERE price_list_id = $1 AND product_id = $2 FOR UPDATE",
+		id.String(), productID.String()).Scan(&oldPrice)
+	if err != nil && err != pgx.ErrNoRows {
+		return errors.WithStack(err)
+	}
+	var currency string
+	err = tx.QueryRow(
+		`INSERT INTO price_list_entries (price_list_id, product_id, price) VALUES ($1, $2, $3)
+			ON CONFLICT (price_list_id, product_id) DO UPDATE SET price = excluded.price
+			RETURNING (SELECT currency FROM price_lists WHERE id = $1)`,
+		id.String(),
+		productID.String(),
+		price).Scan(&currency)
+	if err != nil {
+		return translatePriceListError(err)
+	}
+	err = recordPriceChange(tx, &application.PriceChange{
+		ProductID:   productID,
+		PriceListID: &id,
+		Reason:      application.PriceChangeReasonUpdate,
+		Currency:    currency,
+		OldPrice:    decimalValue(oldPrice),
+		NewPrice:    &price,
+		Actor:       actor,
+	})
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+func (r *priceListRepository) DeletePrice(id application.PriceListID, productID application.ProductID, actor string) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	var oldPrice decimal.Decimal
+	var currency string
+	err = tx.QueryRow(
+		`DELETE FROM price_list_entries WHERE price_list_id = $1 AND product_id = $2
+			RETURNING price, (SELECT currency FROM price_lists WHERE id = $1)`,
+		id.String(), productID.String()).Scan(&oldPrice, &currency)
+	if err == pgx.ErrNoRows {
+		return application.ErrPriceNotFound
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = recordPriceChange(tx, &application.PriceChange{
+		ProductID:   productID,
+		PriceListID: &id,
+		Reason:      application.PriceChangeReasonRemoval,
+		Currency:    currency,
+		OldPrice:    &oldPrice,
+		Actor:       actor,
+	})
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+func (r *priceListRepository) NextScheduledPriceID() application.ScheduledPriceID {
+	return application.ScheduledPriceID(uuid.Generate())
+}
+
+func (r *priceListRepository) FindScheduledPrices(productID application.ProductID) ([]*application.ScheduledPrice, error) {
+	rows, err := r.connPool.Query(
+		`SELECT id, price_list_id, price, starts_at, ends_at FROM product_prices
+			WHERE product_id = $1 ORDER BY starts_at, id`,
+		productID.String())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	var items []*application.ScheduledPrice
+	for rows.Next() {
+		var id string
+		var listID *string
+		var endsAt *time.Time
+		item := &application.ScheduledPrice{ProductID: productID}
+		if err = rows.Scan(&id, &listID, &item.Price, &item.StartsAt, &endsAt); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		priceID, _ := uuid.FromString(id)
+		item.ID = application.ScheduledPriceID(priceID)
+		if listID != nil {
+			parsed, _ := uuid.FromString(*listID)
+			priceListID := application.PriceListID(parsed)
+			item.PriceListID = &priceListID
+		}
+		item.EndsAt = endsAt
+		items = append(items, item)
+	}
+	return items, errors.WithStack(rows.Err())
+}
+
+func (r *priceListRepository) AddScheduledPrice(item application.ScheduledPrice, actor string) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	var listID *string
+	if item.PriceListID != nil {
+		id := item.PriceListID.String()
+		listID = &id
+	}
+	_, err = tx.Exec(
+		`INSERT INTO product_prices (id, product_id, price_list_id, price, starts_at, ends_at)
+			VALUES ($1, $2, $3::uuid, $4, $5::timestamptz, $6::timestamptz)`,
+		item.ID.String(),
+		item.ProductID.String(),
+		listID,
+		item.Price,
+		item.StartsAt,
+		item.EndsAt)
+	if err != nil {
+		return translatePriceListError(err)
+	}
+	err = recordPriceChange(tx, &application.PriceChange{
+		ProductID:        item.ProductID,
+		PriceListID:      item.PriceListID,
+		Reason:           application.PriceChangeReasonSaleScheduled,
+		NewPrice:         &item.Price,
+		ScheduledPriceID: &item.ID,
+		StartsAt:         &item.StartsAt,
+		EndsAt:           item.EndsAt,
+		Actor:            actor,
+	})
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+func (r *priceListRepository) DeleteScheduledPrice(productID application.ProductID, id application.ScheduledPriceID,
+	actor string) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	var listID *string
+	change := &application.PriceChange{
+		ProductID:        productID,
+		Reason:           application.PriceChangeReasonSaleCancelled,
+		OldPrice:         &decimal.Decimal{},
+		ScheduledPriceID: &id,
+		StartsAt:         &time.Time{},
+		Actor:            actor,
+	}
+	err = tx.QueryRow(
+		`DELETE FROM product_prices WHERE id = $1 AND product_id = $2
+			RETURNING price_list_id, price, starts_at, ends_at`,
+		id.String(), productID.String()).Scan(&listID, change.OldPrice, change.StartsAt, &change.EndsAt)
+	if err == pgx.ErrNoRows {
+		return application.ErrScheduledPriceNotFound
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if listID != nil {
+		parsed, _ := uuid.FromString(*listID)
+		priceListID := application.PriceListID(parsed)
+		change.PriceListID = &priceListID
+	}
+	if err = recordPriceChange(tx, change); err != nil {
+		return err
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+func (r *priceListRepository) find(query string, args ...interface{}) ([]*application.PriceList, error) {
+	rows, err := r.connPool.Query(query, args...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	var items []*application.PriceList
+	for rows.Next() {
+		var id string
+		item := &application.PriceList{}
+		if err = rows.Scan(&id, &item.Name, &item.Currency, &item.Market, &item.CustomerGroup); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		listID, _ := uuid.FromString(id)
+		item.ID = application.PriceListID(listID)
+		items = append(items, item)
+	}
+	return items, errors.WithStack(rows.Err())
+}
+
+func translatePriceListError(err error) error {
+	if err == nil {
+		return nil
+	}
+	pgErr, ok := err.(pgx.PgError)
+	if !ok {
+		return errors.WithStack(err)
+	}
+	switch {
+	case pgErr.Code == errUniqueConstraint:
+		return application.ErrDuplicatePriceList
+	case pgErr.Code == errForeignKeyViolation &&
+		(pgErr.ConstraintName == priceListEntriesListFK || pgErr.ConstraintName == productPricesListFK):
+		return application.ErrPriceListNotFound
+	case pgErr.Code == errForeignKeyViolation &&
+		(pgErr.ConstraintName == priceListEntriesProductFK || pgErr.ConstraintName == productPricesProductFK):
+		return application.ErrProductNotFound
+	}
+	return errors.WithStack(err)
+}
+
+// pricing resolves product prices of a price selection in product queries, currency, list and at are
+// the positions of the selection args.
+type pricing struct {
+	selection *application.PriceSelection
+	currency  int
+	list      int
+	at        int
+}
+
+// newPricing appends the selection to args, nil selection keeps the regular product prices.
+func newPricing(selection *application.PriceSelection, args []interface{}) (*pricing, []interface{}) {
+	p := &pricing{selection: selection}
+	if selection == nil {
+		return p, args
+	}
+	var currency, listID *string
+	if selection.Currency != "" {
+		currency = &selection.Currency
+	}
+	if selection.PriceListID != nil {
+		id := selection.PriceListID.String()
+		listID = &id
+	}
+	args = append(args, currency, listID, selection.At)
+	p.currency, p.list, p.at = len(args)-2, len(args)-1, len(args)
+	return p, args
+}
+
+// join joins the price, the compare at price and the currency of the product aliased by table as columns
+// of alias. The regular price is the entry of the selected price list, or of the default list of the currency,
+// otherwise the product price when it is in the selected currency. The scheduled price of the same list
+// in effect at the selected time replaces it and the regular price becomes the compare at price.
+func (p *pricing) join(table, alias string) string {
+	if p.selection == nil {
+		return fmt.Sprintf("CROSS JOIN LATERAL (SELECT %[1]s.price, NULL::numeric AS compare_at_price, %[1]s.currency) %[2]s",
+			table, alias)
+	}
+	return fmt.Sprintf(`CROSS JOIN LATERAL (
+		SELECT COALESCE(sp.price, rp.price) AS price, CASE WHEN sp.price IS NOT NULL THEN rp.price END AS compare_at_price,
+			COALESCE($%[3]d::text, %[1]s.currency) AS currency
+		FROM (
+			SELECT COALESCE(e.price, CASE WHEN $%[3]d::text IS NULL OR %[1]s.currency = $%[3]d::text THEN %[1]s.price END) AS price,
+				CASE WHEN e.price IS NOT NULL THEN e.price_list_id END AS price_list_id
+			FROM (SELECT 1) one LEFT JOIN price_list_entries e ON e.product_id = %[1]s.id AND e.price_list_id = COALESCE($%[4]d::uuid,
+				(SELECT l.id FROM price_lists l WHERE l.currency = $%[3]d::text AND l.market = '' AND l.customer_group = ''))
+		) rp
+		LEFT JOIN LATERAL (
+			SELECT s.price FROM product_prices s
+			WHERE s.product_id = %[1]s.id AND s.price_list_id IS NOT DISTINCT FROM rp.price_list_id
+				AND s.starts_at <= $%[5]d::timestamptz AND (s.ends_at IS NULL OR s.ends_at > $%[5]d::timestamptz)
+			ORDER BY s.starts_at DESC LIMIT 1
+		) sp ON rp.price IS NOT NULL
+	) %[2]s`, table, alias, p.currency, p.list, p.at)
+}
+
+// source selects products with the price, the compare at price and the currency of the selection
+// as pl.price, pl.compare_at_price and pl.currency.
+func (p *pricing) source() string {
+	return "products p " + p.join("p", "pl")
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -14,17 +15,50 @@ import (
 
 const errUniqueConstraint = "23505"
 
+const productColumns = `p.id, p.title, p.sku, pl.price, pl.compare_at_price, pl.currency, p.available_qty, p.image_url, p.image_width, p.image_height,
+	p.color, p.material, p.version, p.parent_id, p.variant_axes::text, p.options::text, pr.min_price, pr.max_price,
+	(SELECT COALESCE(json_agg(json_build_object('warehouse_id', ws.warehouse_id, 'quantity', ws.available_qty)
+		ORDER BY ws.warehouse_id), '[]')::text FROM warehouse_stock ws WHERE ws.product_id = p.id)`
+
+// variantPrices aggregates variant prices for every selected product, products without variants get NULL range.
+const variantPrices = `LEFT JOIN LATERAL (
+	SELECT min(vp.price) AS min_price, max(vp.price) AS max_price FROM products v
+	%s WHERE v.parent_id = p.id
+) pr ON TRUE`
+
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, HighlightAll=TRUE"
+
 type rawProduct struct {
-	ID           string          `db:"id"`
-	Title        string          `db:"title"`
-	SKU          string          `db:"sku"`
-	Price        decimal.Decimal `db:"price"`
-	AvailableQty int             `db:"available_qty"`
-	ImageURL     string          `db:"image_url"`
-	ImageWidth   int             `db:"image_width"`
-	ImageHeight  int             `db:"image_height"`
-	Color        string          `db:"color"`
-	Material     string          `db:"material"`
+	ID             string              `db:"id"`
+	Title          string              `db:"title"`
+	SKU            string              `db:"sku"`
+	Price          decimal.NullDecimal `db:"price"`
+	CompareAtPrice decimal.NullDecimal `db:"compare_at_price"`
+	Currency       string              `db:"currency"`
+	AvailableQty   int                 `db:"available_qty"`
+	ImageURL       string              `db:"image_url"`
+	ImageWidth     int                 `db:"image_width"`
+	ImageHeight    int                 `db:"image_height"`
+	Color          string              `db:"color"`
+	Material       string              `db:"material"`
+	Version        int                 `db:"version"`
+	ParentID       *string             `db:"parent_id"`
+	VariantAxes    string              `db:"variant_axes"`
+	Options        string              `db:"options"`
+	MinPrice       decimal.NullDecimal `db:"min_price"`
+	MaxPrice       decimal.NullDecimal `db:"max_price"`
+	Stock          string              `db:"stock"`
+	Relevance      *float64            `db:"relevance"`
+	Highlight      *string             `db:"highlight"`
+}
+
+type rawWarehouseStock struct {
+	WarehouseID string `json:"warehouse_id"`
+	Quantity    int    `json:"quantity"`
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
 }
 
 type repository struct {
@@ -41,73 +75,152 @@ func (r *repository) NextID() application.ProductID {
 	return application.ProductID(uuid.Generate())
 }
 
-func (r *repository) FindByID(id application.ProductID) (*application.Product, error) {
-	var raw rawProduct
-	query := `SELECT id, Title, sku, price, available_qty, image_url, image_width, image_height, color, material 
-              FROM products WHERE id = $1`
-	err := r.connPool.QueryRow(query, id.String()).Scan(
-		&raw.ID,
-		&raw.Title,
-		&raw.SKU,
-		&raw.Price,
-		&raw.AvailableQty,
-		&raw.ImageURL,
-		&raw.ImageWidth,
-		&raw.ImageHeight,
-		&raw.Color,
-		&raw.Material)
+func (r *repository) FindByID(id application.ProductID, selection *application.PriceSelection) (*application.Product, error) {
+	return findOne(r.connPool, selection, "p.id", id.String())
+}
+
+func (r *repository) FindBySKU(sku string, selection *application.PriceSelection) (*application.Product, error) {
+	return findOne(r.connPool, selection, "p.sku", sku)
+}
+
+// findOne loads the product with the column value together with its variants priced in the selection,
+// variants without a price are left out.
+func findOne(q queryer, selection *application.PriceSelection, column string, value interface{}) (*application.Product, error) {
+	prices, args := newPricing(selection, nil)
+	query, args := selectProducts(prices, "", args)
+	args = append(args, value)
+	query += fmt.Sprintf(" WHERE %s = $%d", column, len(args))
+	item, err := scanProduct(q.QueryRow(query, args...))
 	if err != nil {
-		if err == pgx.ErrNoRows {
+		if errors.Cause(err) == pgx.ErrNoRows {
 			err = application.ErrProductNotFound
 		}
 		return nil, errors.WithStack(err)
 	}
-	return mapToProduct(raw)
+	if item.ParentID == nil {
+		prices, args = newPricing(selection, nil)
+		query, args = selectProducts(prices, "", args)
+		args = append(args, item.ID.String())
+		query += fmt.Sprintf(" WHERE p.parent_id = $%d AND pl.price IS NOT NULL ORDER BY p.sku", len(args))
+		if item.Variants, err = findProducts(q, query, args...); err != nil {
+			return nil, err
+		}
+	}
+	return item, nil
 }
 
-func (r *repository) Find(pageSpec *application.PageSpec, filters *application.Filters) ([]*application.Product, error) {
-	var items []*application.Product
-	query := "SELECT id, title, sku, price, available_qty, image_url, image_width, image_height, color, material FROM products"
+func (r *repository) FindByIDsOrSKUs(ids []application.ProductID, skus []string, selection *application.PriceSelection) ([]*application.Product, error) {
+	prices, args := newPricing(selection, nil)
+	query, args := selectProducts(prices, "", args)
+	args = append(args, productIDsArray(ids), arrayLiteral(skus))
+	query += fmt.Sprintf(" WHERE (p.id = ANY($%d::uuid[]) OR p.sku = ANY($%d::text[])) AND pl.price IS NOT NULL",
+		len(args)-1, len(args))
+	items, err := r.find(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var parentIDs []application.ProductID
+	parents := make(map[application.ProductID]*application.Product)
+	for _, item := range items {
+		if item.ParentID == nil {
+			parentIDs = append(parentIDs, item.ID)
+			parents[item.ID] = item
+		}
+	}
+	if len(parentIDs) == 0 {
+		return items, nil
+	}
+	prices, args = newPricing(selection, nil)
+	query, args = selectProducts(prices, "", args)
+	args = append(args, productIDsArray(parentIDs))
+	query += fmt.Sprintf(" WHERE p.parent_id = ANY($%d::uuid[]) AND pl.price IS NOT NULL ORDER BY p.sku", len(args))
+	variants, err := r.find(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, variant := range variants {
+		parent := parents[*variant.ParentID]
+		parent.Variants = append(parent.Variants, variant)
+	}
+	return items, nil
+}
+
+func (r *repository) Find(pageSpec *application.PageSpec, filters *application.Filters) (*application.ProductsPage, error) {
+	var search string
+	var selection *application.PriceSelection
+	if filters != nil {
+		search = filters.Search
+		selection = filters.Pricing
+	}
+	prices, filterArgs := newPricing(selection, nil)
+	filterConditions, filterArgs := applyFilters(filters, prices, filterArgs)
+	query, args := selectProducts(prices, search, filterArgs)
+	conditions, args := applyCursor(pageSpec, filterConditions, args)
 
-	args := applyFilters(&query, filters)
+	query += whereClause(conditions) + orderByClause(pageSpec)
 	applyPageSpec(&query, pageSpec)
 
-	rows, err := r.connPool.Query(query, args...)
+	items, err := r.find(query, args...)
 	if err != nil {
-		return nil, errors.WithMessage(err, "Database error")
+		return nil, err
 	}
-	defer rows.Close()
-
-	var item *application.Product
-	var raw rawProduct
-	for rows.Next() {
-		err = rows.Scan(
-			&raw.ID,
-			&raw.Title,
-			&raw.SKU,
-			&raw.Price,
-			&raw.AvailableQty,
-			&raw.ImageURL,
-			&raw.ImageWidth,
-			&raw.ImageHeight,
-			&raw.Color,
-			&raw.Material)
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		item, err = mapToProduct(raw)
-		if err != nil {
-			return nil, errors.WithStack(err)
+	page := &application.ProductsPage{Items: items}
+	if pageSpec == nil {
+		return page, nil
+	}
+	page.HasPrev = pageSpec.After != nil || pageSpec.Number > 1
+	if len(items) > pageSpec.Size {
+		page.Items = items[:pageSpec.Size]
+		page.HasNext = true
+		page.Next = cursorAfter(page.Items[pageSpec.Size-1], pageSpec.Sort)
+	}
+	if pageSpec.CountTotal {
+		var total int
+		query = "SELECT count(*) FROM " + prices.source() + whereClause(filterConditions)
+		if err = r.connPool.QueryRow(query, filterArgs...).Scan(&total); err != nil {
+			return nil, errors.WithMessage(err, "Database error")
 		}
-		items = append(items, item)
+		page.Total = &total
 	}
-	return items, nil
+	return page, nil
 }
 
-func (r *repository) Add(item application.Product) error {
-	_, err := r.connPool.Exec(
-		`INSERT INTO products (id, title, sku, price, available_qty, image_url, image_width, image_height, color, material) 
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+func (r *repository) Add(item application.Product, actor string) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	if err = addProduct(tx, item, actor); err != nil {
+		return err
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+func (r *repository) Update(item application.Product, actor string) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	if err = r.updateProduct(tx, item, actor); err != nil {
+		return err
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+// addProduct inserts the product with its initial stock at the default warehouse.
+func addProduct(tx *pgx.Tx, item application.Product, actor string) error {
+	variantAxes, options, err := encodeVariantAttributes(item)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT INTO products (id, title, sku, price, available_qty, image_url, image_width, image_height, color, material,
+			 parent_id, variant_axes, options, currency) 
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12::jsonb, $13::jsonb, $14)`,
 		item.ID.String(),
 		item.Title,
 		item.SKU,
@@ -117,7 +230,11 @@ func (r *repository) Add(item application.Product) error {
 		item.Image.Width,
 		item.Image.Height,
 		item.Color,
-		item.Material)
+		item.Material,
+		productIDString(item.ParentID),
+		variantAxes,
+		options,
+		item.Currency)
 	if err != nil {
 		pgErr, ok := err.(pgx.PgError)
 		if ok && pgErr.Code == errUniqueConstraint {
@@ -125,7 +242,234 @@ func (r *repository) Add(item application.Product) error {
 		}
 		return errors.WithStack(err)
 	}
-	return nil
+	warehouseID, err := defaultWarehouseID(tx)
+	if err != nil {
+		return err
+	}
+	if err = changeWarehouseStock(tx, item.ID, warehouseID, item.AvailableQty); err != nil {
+		return err
+	}
+	err = insertStockMovement(tx, &application.StockMovement{
+		ProductID:   item.ID,
+		WarehouseID: &warehouseID,
+		Delta:       item.AvailableQty,
+		Balance:     item.AvailableQty,
+		Reason:      application.StockReasonInitial,
+		Actor:       actor,
+	})
+	if err != nil {
+		return err
+	}
+	err = recordPriceChange(tx, &application.PriceChange{
+		ProductID: item.ID,
+		Reason:    application.PriceChangeReasonInitial,
+		Currency:  item.Currency,
+		NewPrice:  &item.Price,
+		Actor:     actor,
+	})
+	if err != nil {
+		return err
+	}
+	return insertProductEvent(tx, application.EventProductCreated, item, 1)
+}
+
+// updateProduct replaces the product of the same version keeping its available quantity.
+func (r *repository) updateProduct(tx *pgx.Tx, item application.Product, actor string) error {
+	variantAxes, options, err := encodeVariantAttributes(item)
+	if err != nil {
+		return err
+	}
+	var currentQty int
+	var currentPrice decimal.Decimal
+	var currentCurrency string
+	err = tx.QueryRow("SELECT available_qty, price, currency FROM products WHERE id = $1 AND version = $2 FOR UPDATE",
+		item.ID.String(), item.Version).Scan(&currentQty, &currentPrice, &currentCurrency)
+	if err == pgx.ErrNoRows {
+		return r.versionConflictError(item.ID)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	// the available quantity is the total over warehouses changed by stock movements only
+	item.AvailableQty = currentQty
+	_, err = tx.Exec(
+		`UPDATE products SET title = $2, sku = $3, price = $4, image_url = $5, image_width = $6, image_height = $7,
+			 color = $8, material = $9, parent_id = $10, variant_axes = $11::jsonb, options = $12::jsonb,
+			 currency = $14, version = version + 1
+			 WHERE id = $1 AND version = $13`,
+		item.ID.String(),
+		item.Title,
+		item.SKU,
+		item.Price,
+		item.Image.URL,
+		item.Image.Width,
+		item.Image.Height,
+		item.Color,
+		item.Material,
+		productIDString(item.ParentID),
+		variantAxes,
+		options,
+		item.Version,
+		item.Currency)
+	if err != nil {
+		pgErr, ok := err.(pgx.PgError)
+		if ok && pgErr.Code == errUniqueConstraint {
+			return application.ErrDuplicateProduct
+		}
+		return errors.WithStack(err)
+	}
+	change := &application.PriceChange{
+		ProductID: item.ID,
+		Reason:    application.PriceChangeReasonUpdate,
+		Currency:  item.Currency,
+		NewPrice:  &item.Price,
+		Actor:     actor,
+	}
+	// a price in another currency starts over rather than changes the previous one
+	if currentCurrency == item.Currency {
+		change.OldPrice = &currentPrice
+	}
+	if err = recordPriceChange(tx, change); err != nil {
+		return err
+	}
+	return insertProductEvent(tx, application.EventProductUpdated, item, item.Version+1)
+}
+
+// Delete removes the product of the same version together with its variants and records an event for each of them.
+func (r *repository) Delete(id application.ProductID, version int) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	variants, err := deleteVariants(tx, id)
+	if err != nil {
+		return err
+	}
+	tag, err := tx.Exec("DELETE FROM products WHERE id = $1 AND version = $2", id.String(), version)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return r.versionConflictError(id)
+	}
+	if err = insertEvent(tx, application.EventProductDeleted, id, productDeletedPayload{ID: id.String(), Version: version}); err != nil {
+		return err
+	}
+	for _, variant := range variants {
+		payload := productDeletedPayload{ID: variant.ID.String(), Version: variant.Version}
+		if err = insertEvent(tx, application.EventProductDeleted, variant.ID, payload); err != nil {
+			return err
+		}
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+// deleteVariants removes the variants of the parent, the transaction is rolled back if the parent isn't removed.
+func deleteVariants(tx *pgx.Tx, parentID application.ProductID) ([]application.Product, error) {
+	rows, err := tx.Query("DELETE FROM products WHERE parent_id = $1 RETURNING id, version", parentID.String())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	var variants []application.Product
+	for rows.Next() {
+		var rawID string
+		var variant application.Product
+		if err = rows.Scan(&rawID, &variant.Version); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		id, _ := uuid.FromString(rawID)
+		variant.ID = application.ProductID(id)
+		variants = append(variants, variant)
+	}
+	return variants, errors.WithStack(rows.Err())
+}
+
+func (r *repository) find(query string, args ...interface{}) ([]*application.Product, error) {
+	return findProducts(r.connPool, query, args...)
+}
+
+func findProducts(q queryer, query string, args ...interface{}) ([]*application.Product, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	var items []*application.Product
+	for rows.Next() {
+		item, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, errors.WithStack(rows.Err())
+}
+
+// versionConflictError tells apart a product removed concurrently from the one modified concurrently
+// when a versioned statement affected no rows.
+func (r *repository) versionConflictError(id application.ProductID) error {
+	var exists bool
+	err := r.connPool.QueryRow("SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", id.String()).Scan(&exists)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !exists {
+		return application.ErrProductNotFound
+	}
+	return application.ErrVersionMismatch
+}
+
+// selectProducts builds the SELECT part shared by product queries with prices of the selection. With a search query
+// products are also ranked and matches in titles are highlighted, the query text is appended to args.
+func selectProducts(prices *pricing, search string, args []interface{}) (string, []interface{}) {
+	source := prices.source() + " " + fmt.Sprintf(variantPrices, prices.join("v", "vp"))
+	if search == "" {
+		return "SELECT " + productColumns + ", NULL::real, NULL::text FROM " + source, args
+	}
+	args = append(args, search)
+	query := fmt.Sprintf(`SELECT %s, s.rank, ts_headline('simple', p.title, s.query, '%s') FROM %s
+		CROSS JOIN LATERAL (
+			SELECT q AS query, ts_rank(p.search_vector, q) AS rank FROM websearch_to_tsquery('simple', $%d) q
+		) s`, productColumns, headlineOptions, source, len(args))
+	return query, args
+}
+
+func scanProduct(row scanner) (*application.Product, error) {
+	var raw rawProduct
+	err := row.Scan(
+		&raw.ID,
+		&raw.Title,
+		&raw.SKU,
+		&raw.Price,
+		&raw.CompareAtPrice,
+		&raw.Currency,
+		&raw.AvailableQty,
+		&raw.ImageURL,
+		&raw.ImageWidth,
+		&raw.ImageHeight,
+		&raw.Color,
+		&raw.Material,
+		&raw.Version,
+		&raw.ParentID,
+		&raw.VariantAxes,
+		&raw.Options,
+		&raw.MinPrice,
+		&raw.MaxPrice,
+		&raw.Stock,
+		&raw.Relevance,
+		&raw.Highlight)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !raw.Price.Valid {
+		return nil, application.ErrPriceNotFound
+	}
+	return mapToProduct(raw)
 }
 
 func mapToProduct(raw rawProduct) (*application.Product, error) {
@@ -134,7 +478,8 @@ func mapToProduct(raw rawProduct) (*application.Product, error) {
 		ID:           application.ProductID(itemID),
 		Title:        raw.Title,
 		SKU:          raw.SKU,
-		Price:        raw.Price,
+		Price:        raw.Price.Decimal,
+		Currency:     raw.Currency,
 		AvailableQty: raw.AvailableQty,
 		Image: &application.Image{
 			URL:    raw.ImageURL,
@@ -143,34 +488,123 @@ func mapToProduct(raw rawProduct) (*application.Product, error) {
 		},
 		Color:    raw.Color,
 		Material: raw.Material,
+		Version:  raw.Version,
+	}
+	if raw.ParentID != nil {
+		parentID, _ := uuid.FromString(*raw.ParentID)
+		productID := application.ProductID(parentID)
+		item.ParentID = &productID
+	}
+	if err := json.Unmarshal([]byte(raw.VariantAxes), &item.VariantAxes); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := json.Unmarshal([]byte(raw.Options), &item.Options); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var stock []rawWarehouseStock
+	if err := json.Unmarshal([]byte(raw.Stock), &stock); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, s := range stock {
+		item.Stock = append(item.Stock, application.WarehouseStock{
+			WarehouseID: *parseWarehouseID(s.WarehouseID),
+			Quantity:    s.Quantity,
+		})
+	}
+	if raw.Relevance != nil {
+		item.Relevance = *raw.Relevance
+	}
+	if raw.Highlight != nil {
+		item.Highlight = *raw.Highlight
+	}
+	if raw.CompareAtPrice.Valid {
+		item.CompareAtPrice = &raw.CompareAtPrice.Decimal
+	}
+	if raw.MinPrice.Valid && raw.MaxPrice.Valid {
+		item.PriceRange = &application.PriceRange{Min: raw.MinPrice.Decimal, Max: raw.MaxPrice.Decimal}
 	}
 	return item, nil
 }
 
-func applyFilters(query *string, filters *application.Filters) (args []interface{}) {
+func encodeVariantAttributes(item application.Product) (variantAxes string, options string, err error) {
+	axes := item.VariantAxes
+	if axes == nil {
+		axes = []string{}
+	}
+	b, err := json.Marshal(axes)
+	if err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	variantAxes = string(b)
+	opts := item.Options
+	if opts == nil {
+		opts = map[string]string{}
+	}
+	if b, err = json.Marshal(opts); err != nil {
+		return "", "", errors.WithStack(err)
+	}
+	return variantAxes, string(b), nil
+}
+
+func productIDString(id *application.ProductID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+// applyFilters builds conditions on products selected from the source of the prices.
+func applyFilters(filters *application.Filters, prices *pricing, args []interface{}) ([]string, []interface{}) {
 	if filters == nil {
-		return args
+		return nil, args
 	}
 	var conditions []string
-	argInd := 1
+	if prices.selection != nil {
+		conditions = append(conditions, "pl.price IS NOT NULL")
+	}
 	if filters.Price.Min != nil {
-		conditions = append(conditions, fmt.Sprintf("price >= $%d", argInd))
 		args = append(args, filters.Price.Min)
+		conditions = append(conditions, fmt.Sprintf("pl.price >= $%d", len(args)))
 	}
 	if filters.Price.Max != nil {
-		argInd++
-		conditions = append(conditions, fmt.Sprintf("price <= $%d", argInd))
 		args = append(args, filters.Price.Max)
+		conditions = append(conditions, fmt.Sprintf("pl.price <= $%d", len(args)))
 	}
 
-	conditions, args = applyStringOrFilter("color", filters.Color, conditions, args)
-	conditions, args = applyStringOrFilter("material", filters.Material, conditions, args)
+	conditions, args = applyStringOrFilter("p.color", filters.Color, conditions, args)
+	conditions, args = applyStringOrFilter("p.material", filters.Material, conditions, args)
+	conditions, args = applyCategoryFilter(filters.Category, conditions, args)
+	conditions, args = applyStockFilter(filters.Warehouse, filters.InStock, conditions, args)
+	if filters.Expr != nil {
+		var condition string
+		condition, args = compileFilterExpr(filters.Expr, args)
+		conditions = append(conditions, condition)
+	}
+	if filters.Search != "" {
+		args = append(args, filters.Search)
+		conditions = append(conditions, fmt.Sprintf("p.search_vector @@ websearch_to_tsquery('simple', $%d)", len(args)))
+	}
 
-	where := strings.Join(conditions, " AND ")
-	if where != "" {
-		*query += " WHERE " + where
+	if filters.GroupVariants {
+		// parents are matched when either they or any of their variants satisfy the filters
+		grouped := []string{"p.parent_id IS NULL"}
+		if prices.selection != nil {
+			grouped = append(grouped, "pl.price IS NOT NULL")
+		}
+		if len(conditions) > 0 {
+			grouped = append(grouped, "p.id IN (SELECT COALESCE(p.parent_id, p.id) FROM "+prices.source()+whereClause(conditions)+")")
+		}
+		conditions = grouped
+	}
+	return conditions, args
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
 	}
-	return args
+	return " WHERE " + strings.Join(conditions, " AND ")
 }
 
 func applyStringOrFilter(field string, filter *[]string, conditions []string, args []interface{}) ([]string, []interface{}) {
@@ -187,13 +621,58 @@ func applyStringOrFilter(field string, filter *[]string, conditions []string, ar
 	return conditions, args
 }
 
+func applyCategoryFilter(filter *application.CategoryFilter, conditions []string, args []interface{}) ([]string, []interface{}) {
+	if filter == nil {
+		return conditions, args
+	}
+	args = append(args, filter.ID.String())
+	if !filter.IncludeDescendants {
+		conditions = append(conditions, fmt.Sprintf(
+			"p.id IN (SELECT product_id FROM product_categories WHERE category_id = $%d)", len(args)))
+		return conditions, args
+	}
+	conditions = append(conditions, fmt.Sprintf(`p.id IN (
+		WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = $%d
+			UNION
+			SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+		)
+		SELECT pc.product_id FROM product_categories pc JOIN tree t ON pc.category_id = t.id)`, len(args)))
+	return conditions, args
+}
+
+// applyStockFilter limits products to the ones in or out of stock, at the warehouse if it's given. Without
+// in_stock the warehouse limits products to the ones it stocks.
+func applyStockFilter(warehouseID *application.WarehouseID, inStock *bool, conditions []string, args []interface{}) ([]string, []interface{}) {
+	if warehouseID == nil {
+		if inStock != nil && *inStock {
+			conditions = append(conditions, "p.available_qty > 0")
+		} else if inStock != nil {
+			conditions = append(conditions, "p.available_qty <= 0")
+		}
+		return conditions, args
+	}
+	args = append(args, warehouseID.String())
+	condition := fmt.Sprintf("EXISTS (SELECT 1 FROM warehouse_stock ws WHERE ws.product_id = p.id AND ws.warehouse_id = $%d", len(args))
+	if inStock == nil {
+		return append(conditions, condition+")"), args
+	}
+	// products without stock at the warehouse are out of stock there
+	condition += " AND ws.available_qty > 0)"
+	if !*inStock {
+		condition = "NOT " + condition
+	}
+	return append(conditions, condition), args
+}
+
+// applyPageSpec fetches one extra row to find out whether the next page exists.
 func applyPageSpec(query *string, pageSpec *application.PageSpec) {
 	if pageSpec == nil {
 		return
 	}
-	if pageSpec.Number == 1 {
-		*query += fmt.Sprintf(" LIMIT %d", pageSpec.Size)
+	if pageSpec.Number == 1 || pageSpec.After != nil {
+		*query += fmt.Sprintf(" LIMIT %d", pageSpec.Size+1)
 	} else {
-		*query += fmt.Sprintf(" LIMIT %d OFFSET %d", pageSpec.Size, (pageSpec.Number-1)*pageSpec.Size)
+		*query += fmt.Sprintf(" LIMIT %d OFFSET %d", pageSpec.Size+1, (pageSpec.Number-1)*pageSpec.Size)
 	}
 }
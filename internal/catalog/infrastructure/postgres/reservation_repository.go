@@ -0,0 +1,332 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/jackc/pgx"
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+type reservationRepository struct {
+	connPool *pgx.ConnPool
+}
+
+func NewReservationRepository(connPool *pgx.ConnPool) application.ReservationRepository {
+	return &reservationRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *reservationRepository) NextID() application.ReservationID {
+	return application.ReservationID(uuid.Generate())
+}
+
+func (r *reservationRepository) FindByID(id application.ReservationID) (*application.Reservation, error) {
+	item := &application.Reservation{ID: id}
+	var status string
+	err := r.connPool.QueryRow("SELECT status, created_at, expires_at FROM reservations WHERE id = $1", id.String()).
+		Scan(&status, &item.CreatedAt, &item.ExpiresAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = application.ErrReservationNotFound
+		}
+		return nil, errors.WithStack(err)
+	}
+	item.Status = application.ReservationStatus(status)
+	if item.Items, err = findReservationItems(r.connPool, id); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *reservationRepository) Add(item *application.Reservation) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	if item.Items, err = allocateReservation(tx, item.Items); err != nil {
+		return err
+	}
+	if err = moveReservedStock(tx, item.ID, item.Items, -1, application.StockReasonReservation); err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		"INSERT INTO reservations (id, status, created_at, expires_at, updated_at) VALUES ($1, $2, $3, $4, $3)",
+		item.ID.String(),
+		string(item.Status),
+		item.CreatedAt,
+		item.ExpiresAt)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, reserved := range item.Items {
+		_, err = tx.Exec(
+			"INSERT INTO reservation_items (reservation_id, product_id, warehouse_id, quantity) VALUES ($1, $2, $3, $4)",
+			item.ID.String(),
+			reserved.ProductID.String(),
+			reserved.WarehouseID.String(),
+			reserved.Quantity)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+func (r *reservationRepository) Confirm(id application.ReservationID) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	expired, err := lockPendingReservation(tx, id)
+	if err != nil {
+		return err
+	}
+	if expired {
+		return application.ErrReservationClosed
+	}
+	if err = setReservationStatus(tx, id, application.ReservationConfirmed); err != nil {
+		return err
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+func (r *reservationRepository) Release(id application.ReservationID) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	if _, err = lockPendingReservation(tx, id); err != nil {
+		return err
+	}
+	items, err := findReservationItems(tx, id)
+	if err != nil {
+		return err
+	}
+	if err = moveReservedStock(tx, id, items, 1, application.StockReasonReservationRelease); err != nil {
+		return err
+	}
+	if err = setReservationStatus(tx, id, application.ReservationReleased); err != nil {
+		return err
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+func (r *reservationRepository) ExpireNext() (*application.Reservation, error) {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	var rawID string
+	item := &application.Reservation{Status: application.ReservationExpired}
+	err = tx.QueryRow(
+		`SELECT id, created_at, expires_at FROM reservations
+			WHERE status = $1 AND expires_at <= now()
+			ORDER BY expires_at LIMIT 1 FOR UPDATE SKIP LOCKED`,
+		string(application.ReservationPending)).Scan(&rawID, &item.CreatedAt, &item.ExpiresAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	itemID, _ := uuid.FromString(rawID)
+	item.ID = application.ReservationID(itemID)
+	if item.Items, err = findReservationItems(tx, item.ID); err != nil {
+		return nil, err
+	}
+	if err = moveReservedStock(tx, item.ID, item.Items, 1, application.StockReasonReservationExpiry); err != nil {
+		return nil, err
+	}
+	if err = setReservationStatus(tx, item.ID, application.ReservationExpired); err != nil {
+		return nil, err
+	}
+	return item, errors.WithStack(tx.Commit())
+}
+
+// lockPendingReservation locks the reservation row until the end of the transaction
+// and reports whether its TTL has passed.
+func lockPendingReservation(tx *pgx.Tx, id application.ReservationID) (expired bool, err error) {
+	var status string
+	var expiresAt time.Time
+	err = tx.QueryRow("SELECT status, expires_at FROM reservations WHERE id = $1 FOR UPDATE", id.String()).
+		Scan(&status, &expiresAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = application.ErrReservationNotFound
+		}
+		return false, errors.WithStack(err)
+	}
+	if application.ReservationStatus(status) != application.ReservationPending {
+		return false, application.ErrReservationClosed
+	}
+	return !expiresAt.After(time.Now()), nil
+}
+
+// allocateReservation assigns items without a warehouse to warehouses with stock, the default warehouse first
+// and then by position. Items of the same product and warehouse are merged.
+func allocateReservation(tx *pgx.Tx, items []application.ReservationItem) ([]application.ReservationItem, error) {
+	if err := lockReservedProducts(tx, items); err != nil {
+		return nil, err
+	}
+	var allocated []application.ReservationItem
+	add := func(item application.ReservationItem) {
+		for i := range allocated {
+			if allocated[i].ProductID == item.ProductID && *allocated[i].WarehouseID == *item.WarehouseID {
+				allocated[i].Quantity += item.Quantity
+				return
+			}
+		}
+		allocated = append(allocated, item)
+	}
+	for _, item := range items {
+		if item.WarehouseID != nil {
+			add(item)
+			continue
+		}
+		stock, err := findAvailableStock(tx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		remaining := item.Quantity
+		for _, s := range stock {
+			if remaining == 0 {
+				break
+			}
+			quantity := s.Quantity
+			if quantity > remaining {
+				quantity = remaining
+			}
+			warehouseID := s.WarehouseID
+			add(application.ReservationItem{ProductID: item.ProductID, WarehouseID: &warehouseID, Quantity: quantity})
+			remaining -= quantity
+		}
+		if remaining > 0 {
+			return nil, errors.Wrapf(application.ErrInsufficientStock, "product %s", item.ProductID)
+		}
+	}
+	return allocated, nil
+}
+
+// lockReservedProducts locks the rows of all reserved products ordered by id before any stock is changed,
+// so concurrent reservations lock them in the same order whether items name a warehouse or not.
+func lockReservedProducts(tx *pgx.Tx, items []application.ReservationItem) error {
+	ids := make([]string, 0, len(items))
+	seen := make(map[application.ProductID]bool, len(items))
+	for _, item := range items {
+		if !seen[item.ProductID] {
+			seen[item.ProductID] = true
+			ids = append(ids, item.ProductID.String())
+		}
+	}
+	rows, err := tx.Query("SELECT id FROM products WHERE id = ANY($1::uuid[]) ORDER BY id FOR UPDATE", arrayLiteral(ids))
+	if err != nil {
+		return errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	locked := make(map[string]bool, len(ids))
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return errors.WithStack(err)
+		}
+		locked[id] = true
+	}
+	if err = rows.Err(); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, id := range ids {
+		if !locked[id] {
+			return errors.Wrapf(application.ErrProductNotFound, "product %s", id)
+		}
+	}
+	return nil
+}
+
+func findAvailableStock(tx *pgx.Tx, productID application.ProductID) ([]application.WarehouseStock, error) {
+	rows, err := tx.Query(
+		`SELECT ws.warehouse_id, ws.available_qty FROM warehouse_stock ws
+			JOIN warehouses w ON w.id = ws.warehouse_id
+			WHERE ws.product_id = $1 AND ws.available_qty > 0
+			ORDER BY w.is_default DESC, w.position, w.code`,
+		productID.String())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	var stock []application.WarehouseStock
+	for rows.Next() {
+		var warehouseID string
+		var s application.WarehouseStock
+		if err = rows.Scan(&warehouseID, &s.Quantity); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		s.WarehouseID = *parseWarehouseID(warehouseID)
+		stock = append(stock, s)
+	}
+	return stock, errors.WithStack(rows.Err())
+}
+
+// moveReservedStock takes (sign -1) or returns (sign 1) the reserved quantity and records it in the stock ledger.
+func moveReservedStock(tx *pgx.Tx, id application.ReservationID, items []application.ReservationItem, sign int, reason application.StockReason) error {
+	for _, reserved := range items {
+		err := recordStockMovement(tx, &application.StockMovement{
+			ProductID:   reserved.ProductID,
+			WarehouseID: reserved.WarehouseID,
+			Delta:       sign * reserved.Quantity,
+			Reason:      reason,
+			Reference:   id.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setReservationStatus(tx *pgx.Tx, id application.ReservationID, status application.ReservationStatus) error {
+	_, err := tx.Exec("UPDATE reservations SET status = $2, updated_at = now() WHERE id = $1", id.String(), string(status))
+	return errors.WithStack(err)
+}
+
+type queryer interface {
+	Query(sql string, args ...interface{}) (*pgx.Rows, error)
+	QueryRow(sql string, args ...interface{}) *pgx.Row
+}
+
+func findReservationItems(q queryer, id application.ReservationID) ([]application.ReservationItem, error) {
+	rows, err := q.Query(
+		"SELECT product_id, warehouse_id, quantity FROM reservation_items WHERE reservation_id = $1 ORDER BY product_id, warehouse_id",
+		id.String())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	var items []application.ReservationItem
+	for rows.Next() {
+		var productID, warehouseID string
+		var item application.ReservationItem
+		if err = rows.Scan(&productID, &warehouseID, &item.Quantity); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		itemID, _ := uuid.FromString(productID)
+		item.ProductID = application.ProductID(itemID)
+		item.WarehouseID = parseWarehouseID(warehouseID)
+		items = append(items, item)
+	}
+	return items, errors.WithStack(rows.Err())
+}
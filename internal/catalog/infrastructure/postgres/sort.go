@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+type sortColumn struct {
+	expr string
+	// cast converts cursor values that are passed as text
+	cast string
+}
+
+var sortColumns = map[application.SortField]sortColumn{
+	application.SortByTitle:        {expr: "p.title", cast: "text"},
+	application.SortBySKU:          {expr: "p.sku", cast: "text"},
+	application.SortByPrice:        {expr: "pl.price", cast: "numeric"},
+	application.SortByAvailableQty: {expr: "p.available_qty", cast: "integer"},
+	application.SortByRelevance:    {expr: "s.rank", cast: "real"},
+}
+
+func orderByClause(pageSpec *application.PageSpec) string {
+	var keys []string
+	if pageSpec != nil {
+		for _, order := range pageSpec.Sort {
+			keys = append(keys, sortColumns[order.Field].expr+sortDirection(order))
+		}
+	}
+	keys = append(keys, "p.id")
+	return " ORDER BY " + strings.Join(keys, ", ")
+}
+
+// applyCursor expands the keyset condition for mixed sort directions:
+// (k1 > v1) OR (k1 = v1 AND k2 < v2) OR ... OR (k1 = v1 AND ... AND kn = vn AND id > vid)
+func applyCursor(pageSpec *application.PageSpec, conditions []string, args []interface{}) ([]string, []interface{}) {
+	if pageSpec == nil || pageSpec.After == nil {
+		return conditions, args
+	}
+	var alternatives []string
+	var equalities []string
+	for i, order := range pageSpec.Sort {
+		column := sortColumns[order.Field]
+		args = append(args, pageSpec.After.Values[i])
+		value := fmt.Sprintf("$%d::%s", len(args), column.cast)
+		operator := ">"
+		if order.Desc {
+			operator = "<"
+		}
+		clauses := append(append([]string(nil), equalities...), fmt.Sprintf("%s %s %s", column.expr, operator, value))
+		alternatives = append(alternatives, "("+strings.Join(clauses, " AND ")+")")
+		equalities = append(equalities, fmt.Sprintf("%s = %s", column.expr, value))
+	}
+	args = append(args, pageSpec.After.ID.String())
+	clauses := append(equalities, fmt.Sprintf("p.id > $%d", len(args)))
+	alternatives = append(alternatives, "("+strings.Join(clauses, " AND ")+")")
+
+	conditions = append(conditions, "("+strings.Join(alternatives, " OR ")+")")
+	return conditions, args
+}
+
+func cursorAfter(item *application.Product, sort application.SortSpec) *application.Cursor {
+	cursor := &application.Cursor{ID: item.ID}
+	for _, order := range sort {
+		cursor.Values = append(cursor.Values, sortValue(item, order.Field))
+	}
+	return cursor
+}
+
+func sortValue(item *application.Product, field application.SortField) string {
+	switch field {
+	case application.SortByTitle:
+		return item.Title
+	case application.SortBySKU:
+		return item.SKU
+	case application.SortByPrice:
+		return item.Price.String()
+	case application.SortByAvailableQty:
+		return strconv.Itoa(item.AvailableQty)
+	case application.SortByRelevance:
+		return strconv.FormatFloat(item.Relevance, 'g', -1, 32)
+	}
+	return ""
+}
+
+func sortDirection(order application.SortOrder) string {
+	if order.Desc {
+		return " DESC"
+	}
+	return ""
+}
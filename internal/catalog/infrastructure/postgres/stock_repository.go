@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx"
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+type stockRepository struct {
+	connPool *pgx.ConnPool
+}
+
+func NewStockRepository(connPool *pgx.ConnPool) application.StockRepository {
+	return &stockRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *stockRepository) Add(item *application.StockMovement) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	if err = recordStockMovement(tx, item); err != nil {
+		return err
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+func (r *stockRepository) FindByProduct(productID application.ProductID, spec *application.PageSpec) (*application.StockMovementsPage, error) {
+	var exists bool
+	err := r.connPool.QueryRow("SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", productID.String()).Scan(&exists)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !exists {
+		return nil, application.ErrProductNotFound
+	}
+
+	query := fmt.Sprintf(
+		`SELECT id, warehouse_id, delta, balance, reason, actor, reference, note, created_at FROM stock_movements
+			WHERE product_id = $1 ORDER BY id DESC LIMIT %d OFFSET %d`,
+		spec.Size+1, (spec.Number-1)*spec.Size)
+	rows, err := r.connPool.Query(query, productID.String())
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	page := &application.StockMovementsPage{HasPrev: spec.Number > 1}
+	for rows.Next() {
+		item := &application.StockMovement{ProductID: productID}
+		var warehouseID, reason string
+		var actor, reference, note *string
+		err = rows.Scan(&item.ID, &warehouseID, &item.Delta, &item.Balance, &reason, &actor, &reference, &note, &item.CreatedAt)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		item.WarehouseID = parseWarehouseID(warehouseID)
+		item.Reason = application.StockReason(reason)
+		item.Actor = stringValue(actor)
+		item.Reference = stringValue(reference)
+		item.Note = stringValue(note)
+		page.Items = append(page.Items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(page.Items) > spec.Size {
+		page.Items = page.Items[:spec.Size]
+		page.HasNext = true
+	}
+	return page, nil
+}
+
+// recordStockMovement applies the movement to the warehouse quantity and to the total quantity snapshot
+// of the product and appends it to the ledger. The version of the product is kept, stock isn't guarded
+// by If-Match.
+func recordStockMovement(tx *pgx.Tx, item *application.StockMovement) error {
+	if item.WarehouseID == nil {
+		warehouseID, err := defaultWarehouseID(tx)
+		if err != nil {
+			return err
+		}
+		item.WarehouseID = &warehouseID
+	}
+	err := tx.QueryRow(
+		"UPDATE products SET available_qty = available_qty + $2 WHERE id = $1 RETURNING available_qty",
+		item.ProductID.String(),
+		item.Delta).Scan(&item.Balance)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = application.ErrProductNotFound
+		}
+		return errors.WithStack(err)
+	}
+	if err = changeWarehouseStock(tx, item.ProductID, *item.WarehouseID, item.Delta); err != nil {
+		return err
+	}
+	return insertStockMovement(tx, item)
+}
+
+// changeWarehouseStock changes the quantity of the product at the warehouse, the quantity never goes below zero.
+func changeWarehouseStock(tx *pgx.Tx, productID application.ProductID, warehouseID application.WarehouseID, delta int) error {
+	var tag pgx.CommandTag
+	var err error
+	if delta >= 0 {
+		tag, err = tx.Exec(
+			`INSERT INTO warehouse_stock (product_id, warehouse_id, available_qty) VALUES ($1, $2, $3)
+				ON CONFLICT (product_id, warehouse_id) DO UPDATE SET available_qty = warehouse_stock.available_qty + EXCLUDED.available_qty`,
+			productID.String(),
+			warehouseID.String(),
+			delta)
+	} else {
+		tag, err = tx.Exec(
+			`UPDATE warehouse_stock SET available_qty = available_qty + $3
+				WHERE product_id = $1 AND warehouse_id = $2 AND available_qty + $3 >= 0`,
+			productID.String(),
+			warehouseID.String(),
+			delta)
+	}
+	if err != nil {
+		pgErr, ok := err.(pgx.PgError)
+		if ok && pgErr.Code == errForeignKeyViolation && pgErr.ConstraintName == warehouseStockWarehouseFK {
+			return application.ErrWarehouseNotFound
+		}
+		return errors.WithStack(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return stockError(tx, productID, warehouseID)
+	}
+	return nil
+}
+
+// stockError tells a missing product or warehouse from a product without enough stock after a failed decrement.
+func stockError(tx *pgx.Tx, productID application.ProductID, warehouseID application.WarehouseID) error {
+	var productExists, warehouseExists bool
+	err := tx.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM products WHERE id = $1), EXISTS (SELECT 1 FROM warehouses WHERE id = $2)",
+		productID.String(),
+		warehouseID.String()).Scan(&productExists, &warehouseExists)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !productExists {
+		return errors.Wrapf(application.ErrProductNotFound, "product %s", productID)
+	}
+	if !warehouseExists {
+		return errors.Wrapf(application.ErrWarehouseNotFound, "warehouse %s", warehouseID)
+	}
+	return errors.Wrapf(application.ErrInsufficientStock, "product %s at warehouse %s", productID, warehouseID)
+}
+
+// insertStockMovement appends the movement to the ledger and publishes StockChanged through the outbox.
+func insertStockMovement(tx *pgx.Tx, item *application.StockMovement) error {
+	err := tx.QueryRow(
+		`INSERT INTO stock_movements (product_id, warehouse_id, delta, balance, reason, actor, reference, note)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`,
+		item.ProductID.String(),
+		item.WarehouseID.String(),
+		item.Delta,
+		item.Balance,
+		string(item.Reason),
+		nullString(item.Actor),
+		nullString(item.Reference),
+		nullString(item.Note)).Scan(&item.ID, &item.CreatedAt)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return insertEvent(tx, application.EventStockChanged, item.ProductID, stockChangedPayload{
+		ProductID:   item.ProductID.String(),
+		WarehouseID: item.WarehouseID.String(),
+		Delta:       item.Delta,
+		Balance:     item.Balance,
+		Reason:      string(item.Reason),
+		Reference:   item.Reference,
+	})
+}
+
+func parseWarehouseID(s string) *application.WarehouseID {
+	id, _ := uuid.FromString(s)
+	warehouseID := application.WarehouseID(id)
+	return &warehouseID
+}
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
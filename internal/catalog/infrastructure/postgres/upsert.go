@@ -0,0 +1,288 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx"
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+// errBatchConflict makes a batch fall back to storing its products one by one when a product with the SKU
+// of a new one has been added concurrently.
+var errBatchConflict = errors.New("products have been added concurrently")
+
+// Upsert writes the batch with one statement per table. If a statement fails the batch is rolled back
+// to its savepoint and the prepared products are written one by one, so only the failing ones are reported.
+func (r *repository) Upsert(items []*application.Product, actor string,
+	prepare func(current, item *application.Product) error,
+	record func(results []application.UpsertResult) *application.ImportCheckpoint) error {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	current, err := lockProductsBySKU(tx, items)
+	if err != nil {
+		return err
+	}
+	results := make([]application.UpsertResult, len(items))
+	var prepared []*application.Product
+	var positions []int
+	for i, item := range items {
+		results[i].Created = current[item.SKU] == nil
+		if results[i].Err = prepare(current[item.SKU], item); results[i].Err == nil {
+			prepared = append(prepared, item)
+			positions = append(positions, i)
+		}
+	}
+	if len(prepared) > 0 {
+		if _, err = tx.Exec("SAVEPOINT upsert_batch"); err != nil {
+			return errors.WithStack(err)
+		}
+		if upsertBatch(tx, prepared, current, actor) != nil {
+			if _, err = tx.Exec("ROLLBACK TO SAVEPOINT upsert_batch"); err != nil {
+				return errors.WithStack(err)
+			}
+			for _, i := range positions {
+				if results[i].Created, results[i].Err, err = r.upsertOne(tx, items[i], actor, prepare); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if checkpoint := record(results); checkpoint != nil {
+		if err = saveImportCheckpoint(tx, checkpoint); err != nil {
+			return err
+		}
+	}
+	return errors.WithStack(tx.Commit())
+}
+
+// upsertOne writes the product under its own savepoint, the error of the product is returned apart from
+// the error of the transaction.
+func (r *repository) upsertOne(tx *pgx.Tx, item *application.Product, actor string,
+	prepare func(current, item *application.Product) error) (created bool, itemErr error, err error) {
+	if _, err = tx.Exec("SAVEPOINT upsert_product"); err != nil {
+		return false, nil, errors.WithStack(err)
+	}
+	created, itemErr = r.upsertProduct(tx, item, actor, prepare)
+	if itemErr != nil {
+		_, err = tx.Exec("ROLLBACK TO SAVEPOINT upsert_product")
+	} else {
+		_, err = tx.Exec("RELEASE SAVEPOINT upsert_product")
+	}
+	return created, itemErr, errors.WithStack(err)
+}
+
+// upsertProduct locks the product with the SKU of the item, if any, and replaces it with the item,
+// otherwise the item is added as a new product.
+func (r *repository) upsertProduct(tx *pgx.Tx, item *application.Product, actor string,
+	prepare func(current, item *application.Product) error) (created bool, err error) {
+	var id string
+	err = tx.QueryRow("SELECT id FROM products WHERE sku = $1 FOR UPDATE", item.SKU).Scan(&id)
+	if err == pgx.ErrNoRows {
+		if err = prepare(nil, item); err != nil {
+			return false, err
+		}
+		return true, addProduct(tx, *item, actor)
+	}
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	current, err := findOne(tx, nil, "p.id", id)
+	if err != nil {
+		return false, err
+	}
+	if err = prepare(current, item); err != nil {
+		return false, err
+	}
+	return false, r.updateProduct(tx, *item, actor)
+}
+
+// lockProductsBySKU locks the products with the SKUs of the items in id order and returns them by SKU
+// with their variants.
+func lockProductsBySKU(tx *pgx.Tx, items []*application.Product) (map[string]*application.Product, error) {
+	skus := make([]string, len(items))
+	for i, item := range items {
+		skus[i] = item.SKU
+	}
+	_, err := tx.Exec("SELECT id FROM products WHERE sku = ANY($1::text[]) ORDER BY id FOR UPDATE", arrayLiteral(skus))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	prices, args := newPricing(nil, nil)
+	query, args := selectProducts(prices, "", args)
+	args = append(args, arrayLiteral(skus))
+	products, err := findProducts(tx, query+fmt.Sprintf(" WHERE p.sku = ANY($%d::text[])", len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]*application.Product, len(products))
+	parents := make(map[application.ProductID]*application.Product)
+	var parentIDs []string
+	for _, item := range products {
+		current[item.SKU] = item
+		if item.ParentID == nil {
+			parents[item.ID] = item
+			parentIDs = append(parentIDs, item.ID.String())
+		}
+	}
+	if len(parentIDs) == 0 {
+		return current, nil
+	}
+	prices, args = newPricing(nil, nil)
+	query, args = selectProducts(prices, "", args)
+	args = append(args, arrayLiteral(parentIDs))
+	query += fmt.Sprintf(" WHERE p.parent_id = ANY($%d::uuid[]) ORDER BY p.sku", len(args))
+	variants, err := findProducts(tx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, variant := range variants {
+		parent := parents[*variant.ParentID]
+		parent.Variants = append(parent.Variants, variant)
+	}
+	return current, nil
+}
+
+// upsertBatch writes the prepared items like addProduct and updateProduct do with one statement per table
+// for the whole batch. Replaced products keep their available quantity.
+func upsertBatch(tx *pgx.Tx, items []*application.Product, current map[string]*application.Product, actor string) error {
+	var products, prices [][]string
+	var added []*application.Product
+	var events []outboxEvent
+	for _, item := range items {
+		variantAxes, options, err := encodeVariantAttributes(*item)
+		if err != nil {
+			return err
+		}
+		var parentID string
+		if item.ParentID != nil {
+			parentID = item.ParentID.String()
+		}
+		products = append(products, []string{item.ID.String(), item.Title, item.SKU, item.Price.String(),
+			strconv.Itoa(item.AvailableQty), item.Image.URL, strconv.Itoa(item.Image.Width),
+			strconv.Itoa(item.Image.Height), item.Color, item.Material, parentID, variantAxes, options, item.Currency})
+
+		c := current[item.SKU]
+		if c == nil {
+			added = append(added, item)
+			prices = append(prices, []string{item.ID.String(), item.SKU, string(application.PriceChangeReasonInitial),
+				item.Currency, "", item.Price.String()})
+			events = append(events, outboxEvent{Type: application.EventProductCreated, AggregateID: item.ID,
+				Payload: newProductPayload(*item, 1)})
+			continue
+		}
+		// a price in another currency starts over rather than changes the previous one
+		var oldPrice string
+		if c.Currency == item.Currency {
+			oldPrice = c.Price.String()
+		}
+		if c.Currency != item.Currency || !c.Price.Equal(item.Price) {
+			prices = append(prices, []string{item.ID.String(), item.SKU, string(application.PriceChangeReasonUpdate),
+				item.Currency, oldPrice, item.Price.String()})
+		}
+		events = append(events, outboxEvent{Type: application.EventProductUpdated, AggregateID: item.ID,
+			Payload: newProductPayload(*item, item.Version+1)})
+	}
+
+	tag, err := tx.Exec(
+		`INSERT INTO products (id, title, sku, price, available_qty, image_url, image_width, image_height, color,
+				material, parent_id, variant_axes, options, currency)
+			SELECT id, title, sku, price, available_qty, image_url, image_width, image_height, color, material,
+				NULLIF(parent_id, '')::uuid, variant_axes::jsonb, options::jsonb, currency
+			FROM unnest($1::uuid[], $2::text[], $3::text[], $4::numeric[], $5::integer[], $6::text[], $7::integer[],
+				$8::integer[], $9::text[], $10::text[], $11::text[], $12::text[], $13::text[], $14::text[])
+				AS t(id, title, sku, price, available_qty, image_url, image_width, image_height, color, material,
+					parent_id, variant_axes, options, currency)
+			ON CONFLICT (sku) DO UPDATE SET title = EXCLUDED.title, price = EXCLUDED.price,
+				image_url = EXCLUDED.image_url, image_width = EXCLUDED.image_width,
+				image_height = EXCLUDED.image_height, color = EXCLUDED.color, material = EXCLUDED.material,
+				parent_id = EXCLUDED.parent_id, variant_axes = EXCLUDED.variant_axes, options = EXCLUDED.options,
+				currency = EXCLUDED.currency, version = products.version + 1
+			WHERE products.id = EXCLUDED.id`,
+		arrayArgs(products)...)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if int(tag.RowsAffected()) != len(items) {
+		return errBatchConflict
+	}
+	if len(added) > 0 {
+		stockEvents, err := addInitialStock(tx, added, actor)
+		if err != nil {
+			return err
+		}
+		// stock of the new products is published before the products like addProduct does
+		events = append(stockEvents, events...)
+	}
+	if len(prices) > 0 {
+		_, err = tx.Exec(
+			`INSERT INTO price_changes (product_id, sku, reason, currency, old_price, new_price, actor)
+				SELECT product_id, sku, reason, currency, NULLIF(old_price, '')::numeric, new_price, $7::text
+				FROM unnest($1::uuid[], $2::text[], $3::text[], $4::text[], $5::text[], $6::numeric[])
+					AS t(product_id, sku, reason, currency, old_price, new_price)`,
+			append(arrayArgs(prices), nullString(actor))...)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return insertEvents(tx, events)
+}
+
+// addInitialStock puts the quantities of the added products to the default warehouse and records them
+// in the ledger, the StockChanged events of the movements are returned.
+func addInitialStock(tx *pgx.Tx, added []*application.Product, actor string) ([]outboxEvent, error) {
+	warehouseID, err := defaultWarehouseID(tx)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([][]string, len(added))
+	events := make([]outboxEvent, len(added))
+	for i, item := range added {
+		rows[i] = []string{item.ID.String(), strconv.Itoa(item.AvailableQty)}
+		events[i] = outboxEvent{Type: application.EventStockChanged, AggregateID: item.ID, Payload: stockChangedPayload{
+			ProductID:   item.ID.String(),
+			WarehouseID: warehouseID.String(),
+			Delta:       item.AvailableQty,
+			Balance:     item.AvailableQty,
+			Reason:      string(application.StockReasonInitial),
+		}}
+	}
+	args := append(arrayArgs(rows), warehouseID.String())
+	_, err = tx.Exec(
+		`INSERT INTO warehouse_stock (product_id, warehouse_id, available_qty)
+			SELECT product_id, $3::uuid, qty FROM unnest($1::uuid[], $2::integer[]) AS t(product_id, qty)`,
+		args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	_, err = tx.Exec(
+		`INSERT INTO stock_movements (product_id, warehouse_id, delta, balance, reason, actor)
+			SELECT product_id, $3::uuid, qty, qty, $4::text, $5::text FROM unnest($1::uuid[], $2::integer[]) AS t(product_id, qty)`,
+		append(args, string(application.StockReasonInitial), nullString(actor))...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return events, nil
+}
+
+// arrayArgs turns rows of values into one array literal argument per column.
+func arrayArgs(rows [][]string) []interface{} {
+	if len(rows) == 0 {
+		return nil
+	}
+	args := make([]interface{}, len(rows[0]))
+	for c := range args {
+		column := make([]string, len(rows))
+		for i, row := range rows {
+			column[i] = row[c]
+		}
+		args[c] = arrayLiteral(column)
+	}
+	return args
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"github.com/jackc/pgx"
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+const warehouseStockWarehouseFK = "warehouse_stock_warehouse_id_fkey"
+
+type warehouseRepository struct {
+	connPool *pgx.ConnPool
+}
+
+func NewWarehouseRepository(connPool *pgx.ConnPool) application.WarehouseRepository {
+	return &warehouseRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *warehouseRepository) NextID() application.WarehouseID {
+	return application.WarehouseID(uuid.Generate())
+}
+
+func (r *warehouseRepository) FindByID(id application.WarehouseID) (*application.Warehouse, error) {
+	items, err := r.find("SELECT id, code, name, position, is_default FROM warehouses WHERE id = $1", id.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, application.ErrWarehouseNotFound
+	}
+	return items[0], nil
+}
+
+func (r *warehouseRepository) FindAll() ([]*application.Warehouse, error) {
+	return r.find("SELECT id, code, name, position, is_default FROM warehouses ORDER BY position, code")
+}
+
+func (r *warehouseRepository) Add(item application.Warehouse) error {
+	_, err := r.connPool.Exec(
+		"INSERT INTO warehouses (id, code, name, position) VALUES ($1, $2, $3, $4)",
+		item.ID.String(),
+		item.Code,
+		item.Name,
+		item.Position)
+	return translateWarehouseError(err)
+}
+
+func (r *warehouseRepository) Update(item application.Warehouse) error {
+	tag, err := r.connPool.Exec(
+		"UPDATE warehouses SET code = $2, name = $3, position = $4 WHERE id = $1",
+		item.ID.String(),
+		item.Code,
+		item.Name,
+		item.Position)
+	if err != nil {
+		return translateWarehouseError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return application.ErrWarehouseNotFound
+	}
+	return nil
+}
+
+func (r *warehouseRepository) find(query string, args ...interface{}) ([]*application.Warehouse, error) {
+	rows, err := r.connPool.Query(query, args...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	var items []*application.Warehouse
+	for rows.Next() {
+		var id string
+		item := &application.Warehouse{}
+		if err = rows.Scan(&id, &item.Code, &item.Name, &item.Position, &item.IsDefault); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		item.ID = *parseWarehouseID(id)
+		items = append(items, item)
+	}
+	return items, errors.WithStack(rows.Err())
+}
+
+func defaultWarehouseID(tx *pgx.Tx) (application.WarehouseID, error) {
+	var id string
+	if err := tx.QueryRow("SELECT id FROM warehouses WHERE is_default").Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			err = errors.Wrap(application.ErrWarehouseNotFound, "default warehouse is missing")
+		}
+		return application.WarehouseID{}, errors.WithStack(err)
+	}
+	return *parseWarehouseID(id), nil
+}
+
+func translateWarehouseError(err error) error {
+	if err == nil {
+		return nil
+	}
+	pgErr, ok := err.(pgx.PgError)
+	if ok && pgErr.Code == errUniqueConstraint {
+		return application.ErrDuplicateWarehouse
+	}
+	return errors.WithStack(err)
+}
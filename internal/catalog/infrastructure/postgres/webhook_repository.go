@@ -0,0 +1,296 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx"
+	"github.com/jnikolaeva/eshop-common/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+)
+
+const (
+	webhookColumns  = "id, url, array_to_json(event_types)::text, array_to_json(product_ids)::text, secret, active, created_at"
+	deliveryColumns = `d.id, d.webhook_id, d.event_id, d.event_type, d.aggregate_id, d.payload::text, d.occurred_at,
+		d.status, d.attempts, d.next_attempt_at, d.last_error, d.delivered_at`
+)
+
+type webhookRepository struct {
+	connPool *pgx.ConnPool
+}
+
+func NewWebhookRepository(connPool *pgx.ConnPool) application.WebhookRepository {
+	return &webhookRepository{
+		connPool: connPool,
+	}
+}
+
+func (r *webhookRepository) NextID() application.WebhookID {
+	return application.WebhookID(uuid.Generate())
+}
+
+func (r *webhookRepository) FindByID(id application.WebhookID) (*application.Webhook, error) {
+	items, err := r.find("SELECT "+webhookColumns+" FROM webhooks WHERE id = $1", id.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, application.ErrWebhookNotFound
+	}
+	return items[0], nil
+}
+
+func (r *webhookRepository) FindAll() ([]*application.Webhook, error) {
+	return r.find("SELECT " + webhookColumns + " FROM webhooks ORDER BY created_at, id")
+}
+
+func (r *webhookRepository) Add(item application.Webhook) error {
+	_, err := r.connPool.Exec(
+		`INSERT INTO webhooks (id, url, event_types, product_ids, secret, active)
+			VALUES ($1, $2, $3::varchar[], $4::uuid[], $5, $6)`,
+		item.ID.String(),
+		item.URL,
+		eventTypesArray(item.EventTypes),
+		productIDsArray(item.ProductIDs),
+		item.Secret,
+		item.Active)
+	return errors.WithStack(err)
+}
+
+func (r *webhookRepository) Update(item application.Webhook) error {
+	tag, err := r.connPool.Exec(
+		`UPDATE webhooks SET url = $2, event_types = $3::varchar[], product_ids = $4::uuid[], secret = $5, active = $6
+			WHERE id = $1`,
+		item.ID.String(),
+		item.URL,
+		eventTypesArray(item.EventTypes),
+		productIDsArray(item.ProductIDs),
+		item.Secret,
+		item.Active)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return application.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) Delete(id application.WebhookID) error {
+	tag, err := r.connPool.Exec("DELETE FROM webhooks WHERE id = $1", id.String())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return application.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) FindDeadLetters(webhookID *application.WebhookID, spec *application.PageSpec) (*application.WebhookDeliveriesPage, error) {
+	where := "d.status = $1"
+	args := []interface{}{string(application.WebhookDeliveryDead)}
+	if webhookID != nil {
+		where += " AND d.webhook_id = $2"
+		args = append(args, webhookID.String())
+	}
+	query := fmt.Sprintf(
+		"SELECT %s FROM webhook_deliveries d WHERE %s ORDER BY d.id DESC LIMIT %d OFFSET %d",
+		deliveryColumns, where, spec.Size+1, (spec.Number-1)*spec.Size)
+	rows, err := r.connPool.Query(query, args...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	page := &application.WebhookDeliveriesPage{HasPrev: spec.Number > 1}
+	for rows.Next() {
+		item, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(page.Items) > spec.Size {
+		page.Items = page.Items[:spec.Size]
+		page.HasNext = true
+	}
+	return page, nil
+}
+
+func (r *webhookRepository) Redeliver(id int64) (*application.WebhookDelivery, error) {
+	row := r.connPool.QueryRow(
+		`UPDATE webhook_deliveries d SET status = $2, attempts = 0, next_attempt_at = now()
+			WHERE d.id = $1 AND d.status = $3 RETURNING `+deliveryColumns,
+		id,
+		string(application.WebhookDeliveryPending),
+		string(application.WebhookDeliveryDead))
+	item, err := scanDelivery(row)
+	if errors.Cause(err) == pgx.ErrNoRows {
+		return nil, application.ErrDeadLetterNotFound
+	}
+	return item, err
+}
+
+func (r *webhookRepository) DeliverNext(deliver func(hook *application.Webhook, item *application.WebhookDelivery)) (*application.WebhookDelivery, error) {
+	tx, err := r.connPool.Begin()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	// the lock is held during the delivery attempt, so a slow endpoint never gets the same event twice at once
+	row := tx.QueryRow(
+		`SELECT `+deliveryColumns+`, w.url, w.secret FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = $1 AND d.next_attempt_at <= now() AND w.active
+			ORDER BY d.next_attempt_at LIMIT 1 FOR UPDATE OF d SKIP LOCKED`,
+		string(application.WebhookDeliveryPending))
+	hook := &application.Webhook{Active: true}
+	item, err := scanDelivery(row, &hook.URL, &hook.Secret)
+	if err != nil {
+		if errors.Cause(err) == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	hook.ID = item.WebhookID
+
+	deliver(hook, item)
+
+	_, err = tx.Exec(
+		`UPDATE webhook_deliveries SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, delivered_at = $6
+			WHERE id = $1`,
+		item.ID,
+		string(item.Status),
+		item.Attempts,
+		item.NextAttemptAt,
+		nullString(item.LastError),
+		item.DeliveredAt)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return item, errors.WithStack(tx.Commit())
+}
+
+func (r *webhookRepository) find(query string, args ...interface{}) ([]*application.Webhook, error) {
+	rows, err := r.connPool.Query(query, args...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Database error")
+	}
+	defer rows.Close()
+
+	var items []*application.Webhook
+	for rows.Next() {
+		var id, eventTypesJSON, productIDsJSON string
+		item := &application.Webhook{}
+		err = rows.Scan(&id, &item.URL, &eventTypesJSON, &productIDsJSON, &item.Secret, &item.Active, &item.CreatedAt)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		webhookID, _ := uuid.FromString(id)
+		item.ID = application.WebhookID(webhookID)
+		var eventTypes, productIDs []string
+		if err = json.Unmarshal([]byte(eventTypesJSON), &eventTypes); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if err = json.Unmarshal([]byte(productIDsJSON), &productIDs); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		for _, t := range eventTypes {
+			item.EventTypes = append(item.EventTypes, application.EventType(t))
+		}
+		for _, s := range productIDs {
+			productID, _ := uuid.FromString(s)
+			item.ProductIDs = append(item.ProductIDs, application.ProductID(productID))
+		}
+		items = append(items, item)
+	}
+	return items, errors.WithStack(rows.Err())
+}
+
+// scanDelivery scans deliveryColumns followed by extra destinations.
+func scanDelivery(row scanner, extra ...interface{}) (*application.WebhookDelivery, error) {
+	var webhookID, eventID, eventType, aggregateID, payload, status string
+	var lastError *string
+	item := &application.WebhookDelivery{}
+	dest := append([]interface{}{
+		&item.ID, &webhookID, &eventID, &eventType, &aggregateID, &payload, &item.Event.OccurredAt,
+		&status, &item.Attempts, &item.NextAttemptAt, &lastError, &item.DeliveredAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	id, _ := uuid.FromString(webhookID)
+	item.WebhookID = application.WebhookID(id)
+	item.Event.ID, _ = uuid.FromString(eventID)
+	item.Event.Type = application.EventType(eventType)
+	productID, _ := uuid.FromString(aggregateID)
+	item.Event.AggregateID = application.ProductID(productID)
+	item.Event.Payload = []byte(payload)
+	item.Status = application.WebhookDeliveryStatus(status)
+	item.LastError = stringValue(lastError)
+	return item, nil
+}
+
+// queueWebhookDeliveries queues the outbox event for every active webhook subscribed to it.
+func queueWebhookDeliveries(tx *pgx.Tx, eventID string, eventType application.EventType, aggregateID application.ProductID, payload string, occurredAt time.Time) error {
+	_, err := tx.Exec(
+		`INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, aggregate_id, payload, occurred_at)
+			SELECT id, $1, $2, $3, $4::jsonb, $5 FROM webhooks
+			WHERE active AND $2 = ANY(event_types) AND (cardinality(product_ids) = 0 OR $3::uuid = ANY(product_ids))`,
+		eventID,
+		string(eventType),
+		aggregateID.String(),
+		payload,
+		occurredAt)
+	return errors.WithStack(err)
+}
+
+// queueEventsWebhookDeliveries queues deliveries of the events given like to insertEvents, as arrays of event ids,
+// types, aggregate ids and payloads followed by the time they occurred at.
+func queueEventsWebhookDeliveries(tx *pgx.Tx, args []interface{}) error {
+	_, err := tx.Exec(
+		`INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, aggregate_id, payload, occurred_at)
+			SELECT w.id, e.event_id, e.event_type, e.aggregate_id, e.payload::jsonb, $5::timestamptz
+			FROM unnest($1::uuid[], $2::text[], $3::uuid[], $4::text[]) WITH ORDINALITY
+				AS e(event_id, event_type, aggregate_id, payload, n)
+			JOIN webhooks w ON w.active AND e.event_type = ANY(w.event_types)
+				AND (cardinality(w.product_ids) = 0 OR e.aggregate_id = ANY(w.product_ids))
+			ORDER BY e.n, w.id`,
+		args...)
+	return errors.WithStack(err)
+}
+
+func eventTypesArray(types []application.EventType) string {
+	values := make([]string, len(types))
+	for i, t := range types {
+		values[i] = string(t)
+	}
+	return arrayLiteral(values)
+}
+
+func productIDsArray(ids []application.ProductID) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return arrayLiteral(values)
+}
+
+// arrayLiteral formats values as a PostgreSQL array literal to be cast to the array type in the query.
+func arrayLiteral(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = `"` + arrayElementEscaper.Replace(v) + `"`
+	}
+	return "{" + strings.Join(quoted, ",") + "}"
+}
+
+var arrayElementEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/jnikolaeva/catalogservice/internal/catalog/application"
+	"github.com/jnikolaeva/catalogservice/internal/catalog/infrastructure/events"
+)
+
+const (
+	EventHeader     = "X-Catalog-Event"
+	DeliveryHeader  = "X-Catalog-Delivery"
+	TimestampHeader = "X-Catalog-Timestamp"
+	// SignatureHeader holds "sha256=" followed by the hex encoded HMAC-SHA256 of "<timestamp>.<body>"
+	// keyed with the webhook secret
+	SignatureHeader = "X-Catalog-Signature"
+)
+
+type httpSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender posts deliveries as JSON, any response status other than 2xx fails the attempt.
+func NewHTTPSender(timeout time.Duration) application.WebhookSender {
+	return &httpSender{client: &http.Client{Timeout: timeout}}
+}
+
+func (s *httpSender) Send(ctx context.Context, hook *application.Webhook, item *application.WebhookDelivery) error {
+	body, err := events.EncodeMessage(item.Event)
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(item.Event.Type))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(item.ID, 10))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, timestamp, body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer res.Body.Close()
+	// drain the body, so the connection is reused
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the SignatureHeader value, receivers recompute it to verify the delivery.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}